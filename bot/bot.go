@@ -2,13 +2,16 @@
 package bot
 
 import (
+	"io"
 	"strconv"
 	"math/rand"
+	"os"
 	"github.com/wallnutkraken/gotuskgo/stringer"
 	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/dbwrap"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/serial"
@@ -21,11 +24,21 @@ import (
 // Bot is the object containing everything to operate the GoTuskGo bot
 type Bot struct {
 	appSettings settings.Application
-	brain       tuskbrain.Brain
+	brain       tuskbrain.Generator
+	chats       tuskbrain.Chats
+	// brainLock guards replacing the brain and chats, using them doesn't need it as they're safe for concurrent use
+	brainLock   *sync.RWMutex
+	// filter keeps blocked terms out of the database and generated messages
+	filter      tuskbrain.Filter
 	telegram    *tgbotapi.BotAPI
 	discord     *discordgo.Session
 	db          Database
+	// lock is held while storing and feeding messages, and while saving snapshots, so that a snapshot
+	// never misses a message stored before it
 	lock        *sync.Mutex
+	// loaded is set once the brain was loaded from the snapshot and the database, before then saving
+	// a snapshot would record every stored message as fed. It's guarded by lock.
+	loaded      bool
 	logLine     chan serial.LogLine
 }
 
@@ -35,30 +48,53 @@ var (
 	// with services not working, so that the API Key can be remotely
 	// set via gRPC
 	ErrServiceInit = errors.New("A messaging service failed to initialize")
+	// ErrNotLoaded is returned when saving a snapshot of a brain that wasn't loaded from the database yet
+	ErrNotLoaded = errors.New("The brain wasn't loaded yet")
 )
 
 // Database is the database interface for dbwrap
 type Database interface {
 	GetOffset() int
 	SetOffset(value int) error
-	AddMessage(msg string) error
+	AddMessage(msg string, unix int64, chat string) error
 	GetSubscription(chatID int64) (dbwrap.Subscription, error)
 	AddSubscription(chatID int64) error
 	Unsubscribe(sub dbwrap.Subscription) error
 	GetSubscriptions() ([]dbwrap.Subscription, error)
 	AddSubscribeError(chatID int64, message string) error
 	GetAllMessages() ([]dbwrap.Message, error)
+	GetChatMessages() ([]dbwrap.Message, error)
+	GetMessagesAfter(id int) ([]dbwrap.Message, error)
+	GetLastMessageID() (int, error)
+	DeleteMessages(content string) ([]dbwrap.Message, error)
 }
 
 // New creates a new instance of the bot
 func New(config settings.Application, db Database, logLine chan serial.LogLine) (*Bot, error) {
+	brain, err := tuskbrain.NewGenerator(config.Brain)
+	if err != nil {
+		return nil, errors.WithMessage(err, "NewGenerator")
+	}
+	filter, err := tuskbrain.NewFilter(config.Filter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "NewFilter")
+	}
 	tusk := &Bot{
 		appSettings: config,
-		brain:       tuskbrain.New(config.Brain),
+		brain:       brain,
+		chats:       tuskbrain.NewChats(config.Brain),
 		db:          db,
 		lock:        &sync.Mutex{},
+		brainLock:   &sync.RWMutex{},
+		filter:      filter,
 		logLine:     logLine,
 	}
+	// Initialize it from the snapshot and the database first, the bot keeps running without
+	// the messaging services, and its snapshots have to contain every stored message
+	if err := tusk.LoadBrain(); err != nil {
+		return nil, err
+	}
+
 	// Connect to Telegram
 	tg, err := tgbotapi.NewBotAPI(config.APIs.Telegram)
 	if err != nil {
@@ -76,11 +112,6 @@ func New(config settings.Application, db Database, logLine chan serial.LogLine)
 		}
 		return tusk, ErrServiceInit
 	}
-
-	// Initialize it by feeding it from the database
-	if err := tusk.FillBrainFromDatabase(); err != nil {
-		return nil, err
-	}
 	return tusk, nil
 }
 
@@ -96,11 +127,19 @@ func (b *Bot) logf(message string, args ...interface{}) {
 }
 
 // UpdateSettings changes the settings for the bot and re-initializes the Telegram client,
-// As well as the markov length (if different)
+// As well as the brain, if the new brain settings change how it is fed
 func (b *Bot) UpdateSettings(config settings.Application) error {
 	var err error
 	b.lock.Lock()
 	defer b.lock.Unlock()
+	// Check the brain settings and the blocklist first, so that a bad chain length or pattern
+	// doesn't leave the settings half changed
+	if err := tuskbrain.CheckSettings(config.Brain); err != nil {
+		return errors.WithMessage(err, "Brain")
+	}
+	if err := b.filter.UpdateSettings(config.Filter); err != nil {
+		return errors.WithMessage(err, "Filter")
+	}
 	if config.APIs.Telegram != b.appSettings.APIs.Telegram {
 		// Telegram re-init is needed, re-init with new key
 		b.telegram, err = tgbotapi.NewBotAPI(config.APIs.Telegram)
@@ -115,13 +154,36 @@ func (b *Bot) UpdateSettings(config settings.Application) error {
 			b.logf("Error while re-initializing Discord after settings update: %s", err.Error())
 		}
 	}
-	// Check if the markov chain length changed
-	if config.Brain.ChainLength != b.appSettings.Brain.ChainLength {
-		// Re-init the brain with the new length
-		b.brain = tuskbrain.New(config.Brain)
-		if err := b.FillBrainFromDatabase(); err != nil {
+	// Check if the brain settings changed in a way that needs the brain rebuilt
+	if tuskbrain.NeedsRebuild(b.appSettings.Brain, config.Brain) {
+		// Re-init the brains with the new settings, keeping the old ones in use until the new ones are filled
+		brain, err := tuskbrain.NewGenerator(config.Brain)
+		if err != nil {
+			return errors.WithMessage(err, "NewGenerator")
+		}
+		chats := tuskbrain.NewChats(config.Brain)
+		if err := b.FillBrainFromDatabase(brain, chats); err != nil {
 			return err
 		}
+		b.setBrain(brain, chats)
+		// The old snapshot is useless now, replace it
+		if err := b.saveSnapshot(config.Database.GetSnapshotPath()); err != nil {
+			b.logf("Error saving brain snapshot after settings update: %s", err.Error())
+		}
+	} else {
+		// Otherwise, just let the brain know of the new settings
+		brain := b.currentBrain()
+		brain.UpdateSettings(config.Brain)
+		if config.Brain.ChatBrains != b.appSettings.Brain.ChatBrains {
+			// The chat brains were turned on or off, start them over
+			chats := tuskbrain.NewChats(config.Brain)
+			if err := b.fillChatsFromDatabase(chats); err != nil {
+				return err
+			}
+			b.setBrain(brain, chats)
+		} else {
+			b.currentChats().UpdateSettings(config.Brain)
+		}
 	}
 
 	// Finally, just replace the settings object
@@ -129,20 +191,218 @@ func (b *Bot) UpdateSettings(config settings.Application) error {
 	return nil
 }
 
-// FillBrainFromDatabase fills the markov brain from the messages stored in the database
-func (b *Bot) FillBrainFromDatabase() error {
+// FillBrainFromDatabase fills the given markov brain from the messages stored in the database,
+// and the given chat brains from the messages received in their chats
+func (b *Bot) FillBrainFromDatabase(brain tuskbrain.Generator, chats tuskbrain.Chats) error {
 	msgs, err := b.db.GetAllMessages()
 	if err != nil {
 		return errors.WithMessage(err, "[TUSK]GetAllMessages")
 	}
 	// Go through and feed all the messages to the chain
 	for _, message := range msgs {
-		b.brain.Feed(message.Content)
+		brain.FeedAt(message.Unix, message.Content)
+		chats.FeedAt(message.Chat, message.Unix, message.Content)
+	}
+	return nil
+}
+
+// fillChatsFromDatabase fills the given chat brains from the messages stored in the database,
+// if they're enabled
+func (b *Bot) fillChatsFromDatabase(chats tuskbrain.Chats) error {
+	if !chats.Enabled() {
+		return nil
+	}
+	msgs, err := b.db.GetChatMessages()
+	if err != nil {
+		return errors.WithMessage(err, "[TUSK]GetChatMessages")
+	}
+	for _, message := range msgs {
+		chats.FeedAt(message.Chat, message.Unix, message.Content)
+	}
+	return nil
+}
+
+// currentBrain returns the markov brain in use, which is fed every message
+func (b *Bot) currentBrain() tuskbrain.Generator {
+	b.brainLock.RLock()
+	defer b.brainLock.RUnlock()
+	return b.brain
+}
+
+// currentChats returns the chat brains in use
+func (b *Bot) currentChats() tuskbrain.Chats {
+	b.brainLock.RLock()
+	defer b.brainLock.RUnlock()
+	return b.chats
+}
+
+// brainFor returns the markov brain to generate messages for the given chat with, which is the brain of
+// the chat if it was fed enough messages, or the brain fed every message otherwise. It never generates
+// messages with blocked terms.
+func (b *Bot) brainFor(chat string) tuskbrain.Generator {
+	return tuskbrain.NewFiltered(b.chatBrain(chat), b.filter)
+}
+
+// sampledBrainFor returns the markov brain to generate messages for the given chat with, like brainFor,
+// generating with the given sampling instead of the one in the settings
+func (b *Bot) sampledBrainFor(chat string, sampling gomarkov.Sampling) tuskbrain.Generator {
+	return tuskbrain.NewFiltered(tuskbrain.WithSampling(b.chatBrain(chat), sampling), b.filter)
+}
+
+// chatBrain returns the brain of the given chat if it was fed enough messages, or the brain fed
+// every message otherwise
+func (b *Bot) chatBrain(chat string) tuskbrain.Generator {
+	b.brainLock.RLock()
+	defer b.brainLock.RUnlock()
+	if brain, ok := b.chats.Brain(chat); ok {
+		return brain
+	}
+	return b.brain
+}
+
+// blocked reports whether the given received message contains a blocked term, logging the term if it does.
+// Blocked messages are neither stored nor fed.
+func (b *Bot) blocked(message string) bool {
+	term, blocked := b.filter.Blocked(message)
+	if blocked {
+		b.logf("Ignoring a message containing the blocked term [%s]", term)
+	}
+	return blocked
+}
+
+// setBrain replaces the markov brain and chat brains in use
+func (b *Bot) setBrain(brain tuskbrain.Generator, chats tuskbrain.Chats) {
+	b.brainLock.Lock()
+	defer b.brainLock.Unlock()
+	b.brain = brain
+	b.chats = chats
+}
+
+// feed feeds the given messages, received in the given chat at the given Unix time,
+// to the markov brain and the brain of the chat
+func (b *Bot) feed(chat string, unix int64, messages ...string) {
+	b.brainLock.RLock()
+	defer b.brainLock.RUnlock()
+	b.brain.FeedAt(unix, messages...)
+	b.chats.FeedAt(chat, unix, messages...)
+}
+
+// telegramChat returns the chat of the brain for the given Telegram chat ID
+func telegramChat(chatID int64) string {
+	return "telegram:" + strconv.FormatInt(chatID, 10)
+}
+
+// discordChat returns the chat of the brain for the given Discord message, which is the
+// guild it was sent in, or the channel for messages sent outside of guilds
+func discordChat(message *discordgo.MessageCreate) string {
+	if message.GuildID != "" {
+		return "discord:" + message.GuildID
+	}
+	return "discord:" + message.ChannelID
+}
+
+// LoadBrain initializes the markov brain from the snapshot stored alongside the database,
+// feeding it only the messages that came after the snapshot was made. If there is no usable
+// snapshot, the brain is filled from the entire database and a new snapshot is saved.
+func (b *Bot) LoadBrain() error {
+	// Discord messages can arrive while loading, wait with storing them until the brain is loaded
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	snapshotPath := b.appSettings.Database.GetSnapshotPath()
+	if _, ok := b.currentBrain().(tuskbrain.Snapshotter); !ok {
+		// Only the markov chain is snapshotted
+		if err := b.FillBrainFromDatabase(b.currentBrain(), b.currentChats()); err != nil {
+			return err
+		}
+		b.loaded = true
+		return nil
+	}
+	brain, lastID, err := tuskbrain.LoadSnapshot(snapshotPath, b.appSettings.Brain)
+	if err != nil {
+		if !os.IsNotExist(errors.Cause(err)) {
+			b.logf("Could not use brain snapshot, rebuilding: %s", err.Error())
+		}
+		// Forget whatever was fed while loading, it's in the database too
+		b.currentBrain().Reset()
+		if err := b.FillBrainFromDatabase(b.currentBrain(), b.currentChats()); err != nil {
+			return err
+		}
+		b.loaded = true
+		if err := b.saveSnapshot(snapshotPath); err != nil {
+			b.logf("Error saving brain snapshot: %s", err.Error())
+		}
+		return nil
+	}
+	// Feed only the messages newer than the snapshot
+	msgs, err := b.db.GetMessagesAfter(lastID)
+	if err != nil {
+		return errors.WithMessage(err, "[TUSK]GetMessagesAfter")
+	}
+	for _, message := range msgs {
+		brain.FeedAt(message.Unix, message.Content)
+	}
+	// The chat brains are not in the snapshot, they're filled from every message received in a chat
+	chats := b.currentChats()
+	if err := b.fillChatsFromDatabase(chats); err != nil {
+		return err
+	}
+	b.setBrain(brain, chats)
+	b.loaded = true
+	if len(msgs) != 0 {
+		// Save the caught up brain, so that the next start doesn't replay the same messages
+		if err := b.saveSnapshot(snapshotPath); err != nil {
+			b.logf("Error saving brain snapshot: %s", err.Error())
+		}
 	}
 	return nil
 }
 
-// HandleInline processes and inline request
+// SaveSnapshot saves the current state of the markov brain alongside the database
+func (b *Bot) SaveSnapshot() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.saveSnapshot(b.appSettings.Database.GetSnapshotPath())
+}
+
+// PruneBrain removes rarely fed transitions and links from the markov chain, as set in the brain settings,
+// and saves a new snapshot if anything was removed
+func (b *Bot) PruneBrain() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	chatTransitions, chatLinks := b.currentChats().Prune()
+	if chatTransitions != 0 || chatLinks != 0 {
+		b.logf("Pruned %d transitions and %d links from the chat brains", chatTransitions, chatLinks)
+	}
+	pruner, ok := b.currentBrain().(tuskbrain.Pruner)
+	if !ok {
+		return nil
+	}
+	transitions, links := pruner.Prune()
+	if transitions == 0 && links == 0 {
+		return nil
+	}
+	b.logf("Pruned %d transitions and %d links from the brain", transitions, links)
+	return b.saveSnapshot(b.appSettings.Database.GetSnapshotPath())
+}
+
+// saveSnapshot saves the brain snapshot to the given path, if the brain can be snapshotted.
+// Returns ErrNotLoaded if the brain wasn't loaded yet. The caller should hold the lock.
+func (b *Bot) saveSnapshot(path string) error {
+	if !b.loaded {
+		return ErrNotLoaded
+	}
+	snapshotter, ok := b.currentBrain().(tuskbrain.Snapshotter)
+	if !ok {
+		return nil
+	}
+	lastID, err := b.db.GetLastMessageID()
+	if err != nil {
+		return errors.WithMessage(err, "GetLastMessageID")
+	}
+	return errors.WithMessage(snapshotter.SaveSnapshot(path, lastID), "SaveSnapshot")
+}
+
+// HandleInline processes and inline request, using the query as the topic of the message
 func (b *Bot) HandleInline(update tgbotapi.Update) error {
 	// Create a response for saying a message
 	sayResponse := tgbotapi.InlineQueryResultArticle{
@@ -150,7 +410,7 @@ func (b *Bot) HandleInline(update tgbotapi.Update) error {
 		ID: strconv.Itoa(rand.Int()),
 		Title: "Say something",
 		InputMessageContent: tgbotapi.InputTextMessageContent{
-			Text: b.brain.Generate(),
+			Text: b.brainFor("").GenerateFrom(update.InlineQuery.Query),
 		},
 	}
 	_, err := b.telegram.AnswerInlineQuery(tgbotapi.InlineConfig{
@@ -208,13 +468,18 @@ func (b *Bot) GetMessagesTelegram() error {
 			// And continue the loop, don't add this message to db/brain
 			continue
 		}
+		if b.blocked(update.Message.Text) {
+			continue
+		}
 
 		// Save the update content to the database
-		if err := b.db.AddMessage(update.Message.Text); err != nil {
+		received := int64(update.Message.Date)
+		chat := telegramChat(update.Message.Chat.ID)
+		if err := b.db.AddMessage(update.Message.Text, received, chat); err != nil {
 			return errors.WithMessagef(err, "AddMessage [%d]", offset)
 		}
 		// Add it to the markov brain
-		b.brain.Feed(update.Message.Text)
+		b.feed(chat, received, update.Message.Text)
 	}
 	// Update the offset
 	if err := b.db.SetOffset(offset + 1); err != nil {
@@ -272,29 +537,138 @@ func (b *Bot) onDiscordMessage(discord *discordgo.Session, message *discordgo.Me
 		// Return upon finishing handling commands, do not let a command message be saved
 		return
 	}
-	// Just a regular message, add it to the bot
-	if err := b.db.AddMessage(message.Content); err != nil {
+	// Just a regular message, add it to the bot unless it's blocked
+	if b.blocked(message.Content) {
+		return
+	}
+	// Store and feed it at once, so that it's either in the next snapshot or newer than it
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	received := time.Now().Unix()
+	chat := discordChat(message)
+	if err := b.db.AddMessage(message.Content, received, chat); err != nil {
 		b.logf("Error saving discord message [%s] to database: %s", message.Content, err.Error())
 	}
-	b.brain.Feed(message.Content)
+	b.feed(chat, received, message.Content)
 }
 
 // AddMessages adds the given array of messages to the database and the markov chain
 func (b *Bot) AddMessages(msgs []string) error {
+	// Leave out the blocked messages
+	allowed := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		if !b.blocked(msg) {
+			allowed = append(allowed, msg)
+		}
+	}
+	msgs = allowed
+	b.lock.Lock()
+	defer b.lock.Unlock()
 	// Add it to the database first, so if it fai.conls, there's no inconsistency between the database
 	// and the chain
+	received := time.Now().Unix()
 	for _, msg := range msgs {
-		if err := b.db.AddMessage(msg); err != nil {
+		if err := b.db.AddMessage(msg, received, ""); err != nil {
 			return errors.WithMessage(err, "AddMessage to DB")
 		}
 	}
 	// And add it to the chain
+	links := b.currentBrain().FeedAt(received, msgs...)
+	b.logf("Added %d messages to the brain, with %d new links", len(msgs), links)
+	return nil
+}
+
+// BlendCorpus blends the messages read from the given Reader, separated by the given delimiter or by
+// newlines, into the markov chain with the given weight, without storing them, so that a curated corpus
+// can make up part of the brain. Blocked messages are left out. The corpus is read one message at a time
+// without holding up the bot, so it can be of any size. The blended messages are kept in the snapshot,
+// but lost when the brain is rebuilt from the database.
+// Returns the amount of messages blended, and of transitions the brain didn't have before.
+func (b *Bot) BlendCorpus(r io.Reader, delimiter string, weight float64) (int, int, error) {
+	merger, ok := b.currentBrain().(tuskbrain.Merger)
+	if !ok {
+		return 0, 0, tuskbrain.ErrUnsupported
+	}
 	b.lock.Lock()
-	b.brain.Feed(msgs...)
+	brainSettings := b.appSettings.Brain
 	b.lock.Unlock()
+	corpus := tuskbrain.New(brainSettings)
+	fed, err := corpus.FeedFrom(r, delimiter, b.blocked)
+	if err != nil {
+		return 0, 0, errors.WithMessage(err, "FeedFrom")
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	// The brain may have been rebuilt while reading, blend into the one in use now
+	if merger, ok = b.currentBrain().(tuskbrain.Merger); !ok {
+		return 0, 0, tuskbrain.ErrUnsupported
+	}
+	diff, err := merger.Diff(corpus)
+	if err != nil {
+		return 0, 0, errors.WithMessage(err, "Diff")
+	}
+	if err := merger.Merge(corpus, weight); err != nil {
+		return 0, 0, errors.WithMessage(err, "Merge")
+	}
+	b.logf("Blended %d messages into the brain with weight %g, with %d new transitions", fed, weight, len(diff.Added))
+	// The messages aren't in the database, only the snapshot keeps them across restarts
+	if err := b.saveSnapshot(b.appSettings.Database.GetSnapshotPath()); err != nil {
+		b.logf("Error saving brain snapshot after blending a corpus: %s", err.Error())
+	}
+	return fed, len(diff.Added), nil
+}
+
+// RemoveMessages deletes every copy of the given messages from the database, and removes them
+// from the markov chain, so that the bot forgets them without a restart
+func (b *Bot) RemoveMessages(msgs []string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for _, msg := range msgs {
+		deleted, err := b.db.DeleteMessages(msg)
+		if err != nil {
+			return errors.WithMessage(err, "DeleteMessages from DB")
+		}
+		// Unfeed it once for every time it was fed, with the weight it was fed with
+		for _, message := range deleted {
+			b.currentBrain().UnfeedAt(message.Unix, message.Content)
+			b.currentChats().UnfeedAt(message.Chat, message.Unix, message.Content)
+		}
+	}
+	// The snapshot still contains the removed messages, replace it
+	if err := b.saveSnapshot(b.appSettings.Database.GetSnapshotPath()); err != nil {
+		b.logf("Error saving brain snapshot after removing messages: %s", err.Error())
+	}
 	return nil
 }
 
+// Regenerate makes up a message sent out earlier again, from the seed it was logged with and the
+// Telegram chat it was sent to, 0 for the messages sent to every chat. It's only the same message
+// as long as nothing was fed to or removed from the brain since, which includes received messages.
+func (b *Bot) Regenerate(chatID int64, seed int64) string {
+	chat := ""
+	if chatID != 0 {
+		chat = telegramChat(chatID)
+	}
+	return b.chatBrain(chat).Regenerate(seed)
+}
+
+// BrainStats returns the statistics of the markov chain, with the top most frequent words
+// The brain is safe for concurrent use, so this doesn't hold up receiving messages.
+func (b *Bot) BrainStats(top int) gomarkov.Stats {
+	return b.currentBrain().Stats(top)
+}
+
+// ExportBrain writes the markov chain to the given Writer in the given format, either all of it
+// or just the neighbourhood of the given word up to the given depth
+func (b *Bot) ExportBrain(w io.Writer, format gomarkov.ExportFormat, word string, depth int) error {
+	exporter, ok := b.currentBrain().(tuskbrain.Exporter)
+	if !ok {
+		return tuskbrain.ErrUnsupported
+	}
+	return exporter.Export(w, format, word, depth)
+}
+
 // sendMessage attempts to send a message to the given chat
 func (b *Bot) sendMessage(chatID int64, message string) error {
 	msg := tgbotapi.NewMessage(chatID, message)
@@ -312,10 +686,24 @@ func (b *Bot) SendTUSK() error {
 	if err != nil {
 		return errors.WithMessage(err, "GetSubscriptions")
 	}
-	// Generate a message
-	message := b.brain.Generate()
+	// Generate a message, log the seed so that it can be regenerated
+	message, seed := b.brainFor("").GenerateSeeded()
+	if message == "" {
+		b.log("Not sending out an empty message, every generated message may have been blocked")
+	} else {
+		b.logf("Sending out a message generated with seed %d", seed)
+	}
 	for _, sub := range subscriptions {
-		err := b.sendMessage(sub.ChatID, message)
+		chatMessage := message
+		// Chats with a brain of their own get a message of their own
+		if brain, ok := b.currentChats().Brain(telegramChat(sub.ChatID)); ok {
+			chatMessage, seed = tuskbrain.NewFiltered(brain, b.filter).GenerateSeeded()
+			b.logf("Sending out a message to chat %d generated from its brain with seed %d", sub.ChatID, seed)
+		}
+		if chatMessage == "" {
+			continue
+		}
+		err := b.sendMessage(sub.ChatID, chatMessage)
 		if err != nil {
 			// An error occurred, unsubscribe the chat. Ignore errors.
 			b.db.AddSubscribeError(sub.ChatID, err.Error())
@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wallnutkraken/gotuskgo/tuskbrain"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/dbwrap"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/serial"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// memoryDatabase keeps messages in memory, the methods the tests don't use aren't implemented
+type memoryDatabase struct {
+	Database
+	messages []dbwrap.Message
+	lastID   int
+}
+
+func (m *memoryDatabase) AddMessage(msg string, unix int64, chat string) error {
+	m.lastID++
+	m.messages = append(m.messages, dbwrap.Message{ID: m.lastID, Content: msg, Unix: unix, Chat: chat})
+	return nil
+}
+
+func (m *memoryDatabase) GetAllMessages() ([]dbwrap.Message, error) {
+	return m.GetMessagesAfter(0)
+}
+
+func (m *memoryDatabase) GetMessagesAfter(id int) ([]dbwrap.Message, error) {
+	var after []dbwrap.Message
+	for _, msg := range m.messages {
+		if msg.ID > id {
+			after = append(after, msg)
+		}
+	}
+	return after, nil
+}
+
+func (m *memoryDatabase) DeleteMessages(content string) ([]dbwrap.Message, error) {
+	var deleted, kept []dbwrap.Message
+	for _, msg := range m.messages {
+		if msg.Content == content {
+			deleted = append(deleted, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	m.messages = kept
+	return deleted, nil
+}
+
+func (m *memoryDatabase) GetLastMessageID() (int, error) {
+	return m.lastID, nil
+}
+
+// newTestBot creates a bot without messaging services, storing its messages in memory and its
+// snapshots in the given directory. Its brain isn't loaded yet.
+func newTestBot(t *testing.T, db Database, dir string) *Bot {
+	config := settings.Default
+	config.Database.Path = filepath.Join(dir, "tusk.db")
+	brain, err := tuskbrain.NewGenerator(config.Brain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := tuskbrain.NewFilter(config.Filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Bot{
+		appSettings: config,
+		brain:       brain,
+		chats:       tuskbrain.NewChats(config.Brain),
+		brainLock:   &sync.RWMutex{},
+		filter:      filter,
+		db:          db,
+		lock:        &sync.Mutex{},
+		logLine:     make(chan serial.LogLine, 100),
+	}
+}
+
+func Test_Removed_messages_are_deleted_and_forgotten(t *testing.T) {
+	db := &memoryDatabase{}
+	tusk := newTestBot(t, db, t.TempDir())
+	if err := tusk.LoadBrain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tusk.AddMessages([]string{"one quick brown fox", "red apples grow on trees", "red apples grow on trees"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tusk.RemoveMessages([]string{"red apples grow on trees"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.messages) != 1 || db.messages[0].Content != "one quick brown fox" {
+		t.Errorf("database has %+v, expected only the kept message", db.messages)
+	}
+	// Every copy of the message is unfed, so nothing of it is left in the brain
+	for i := 0; i < 20; i++ {
+		if generated := tusk.currentBrain().Generate(); generated != "one quick brown fox" {
+			t.Fatalf("generated %q, expected only the kept message", generated)
+		}
+	}
+	if stats := tusk.BrainStats(10); stats.Vocabulary != len(strings.Fields("one quick brown fox")) {
+		t.Errorf("stats %+v, expected only the words of the kept message", stats)
+	}
+	// And the snapshot is replaced without it
+	if _, err := os.Stat(tusk.appSettings.Database.GetSnapshotPath()); err != nil {
+		t.Errorf("no snapshot saved after removing messages: %v", err)
+	}
+}
+
+func Test_Snapshots_are_only_saved_once_the_brain_is_loaded(t *testing.T) {
+	db := &memoryDatabase{}
+	if err := db.AddMessage("one quick brown fox", 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	tusk := newTestBot(t, db, dir)
+	// An empty brain would be saved as if it had every stored message
+	if err := tusk.SaveSnapshot(); err != ErrNotLoaded {
+		t.Errorf("saved a snapshot before loading the brain, error %v", err)
+	}
+	if _, err := os.Stat(tusk.appSettings.Database.GetSnapshotPath()); !os.IsNotExist(err) {
+		t.Errorf("snapshot written before loading the brain: %v", err)
+	}
+
+	if err := tusk.LoadBrain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tusk.SaveSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	// The next start loads the snapshot, which has the stored message
+	restarted := newTestBot(t, db, dir)
+	if err := restarted.LoadBrain(); err != nil {
+		t.Fatal(err)
+	}
+	if generated := restarted.currentBrain().Generate(); generated != "one quick brown fox" {
+		t.Errorf("generated %q after a restart, expected the stored message", generated)
+	}
+}
+
+func Test_Blended_corpus_is_fed_but_not_stored(t *testing.T) {
+	db := &memoryDatabase{}
+	tusk := newTestBot(t, db, t.TempDir())
+	if err := tusk.LoadBrain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tusk.AddMessages([]string{"one quick brown fox"}); err != nil {
+		t.Fatal(err)
+	}
+	before := tusk.BrainStats(0)
+	blended, added, err := tusk.BlendCorpus(strings.NewReader("red apples grow on trees\n"), "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blended != 1 {
+		t.Errorf("blended %d messages, expected 1", blended)
+	}
+	if after := tusk.BrainStats(0); added == 0 || after.Transitions-before.Transitions != added {
+		t.Errorf("blended %d new transitions, the brain went from %+v to %+v", added, before, after)
+	}
+	if generated := tusk.currentBrain().GenerateAround("apples"); generated != "red apples grow on trees" {
+		t.Errorf("generated %q around a blended word", generated)
+	}
+	if len(db.messages) != 1 {
+		t.Errorf("database has %+v, expected the blended messages to be left out", db.messages)
+	}
+}
+
+func Test_Blocked_messages_are_replied_to_with_a_stand_in(t *testing.T) {
+	db := &memoryDatabase{}
+	tusk := newTestBot(t, db, t.TempDir())
+	if err := tusk.LoadBrain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tusk.AddMessages([]string{"one quick brown fox"}); err != nil {
+		t.Fatal(err)
+	}
+	filter, err := tuskbrain.NewFilter(settings.Filter{Words: []string{"fox"}, Attempts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tusk.filter = filter
+	// Every attempt has the blocked word, which would leave nothing to send
+	generated := tusk.brainFor("").GenerateFrom("")
+	if generated != "" {
+		t.Fatalf("generated %q, expected every attempt to be blocked", generated)
+	}
+	if reply := sayable(generated); reply == "" {
+		t.Error("nothing to reply with when every attempt is blocked")
+	}
+}
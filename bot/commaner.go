@@ -5,7 +5,10 @@ import (
 	"github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
 	"github.com/wallnutkraken/gotuskgo/stringer"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain"
+	"strings"
 )
 
 // TgCommander contains functions for dealing with a specific command in Telegram
@@ -24,12 +27,27 @@ var telegramCmd = TgCommander{
 	"/subscribe":   Subscribe,
 	"/unsubscribe": Unsubscribe,
 	"/say":         Say,
+	"/about":       About,
+	"/nickname":    Nickname,
+	"/calm":        Calm,
+	"/chaos":       Chaos,
 }
 
 var discordCmd = DiscordCommander{
-	"!tusk": tuskDiscord,
+	"!tusk":     tuskDiscord,
+	"!about":    aboutDiscord,
+	"!nickname": nicknameDiscord,
+	"!calm":     calmDiscord,
+	"!chaos":    chaosDiscord,
 }
 
+var (
+	// calmSampling makes common words more likely, for more coherent messages than usual
+	calmSampling = gomarkov.Sampling{Temperature: 0.5, TopP: 0.9}
+	// chaosSampling makes rare words more likely, for more chaotic messages than usual
+	chaosSampling = gomarkov.Sampling{Temperature: 2}
+)
+
 // Subscribe deals with commands regarding subscriptions
 func Subscribe(update tgbotapi.Update, bot *Bot) error {
 	// Check for an existing subscription
@@ -66,16 +84,106 @@ func Unsubscribe(update tgbotapi.Update, bot *Bot) error {
 	return bot.db.Unsubscribe(sub)
 }
 
-// Say sends a new message to the specific chat
+// Say sends a new message to the specific chat, about the topic given after the command, if any
 func Say(update tgbotapi.Update, bot *Bot) error {
-	return bot.sendMessage(update.Message.Chat.ID, bot.brain.Generate())
+	brain := bot.brainFor(telegramChat(update.Message.Chat.ID))
+	return bot.sendMessage(update.Message.Chat.ID, sayable(brain.GenerateFrom(update.Message.CommandArguments())))
 }
 
 func tuskDiscord(message *discordgo.MessageCreate, bot *Bot) error {
-	_, err := bot.discord.ChannelMessageSend(message.ChannelID, bot.brain.Generate())
+	// Anything after the command is the topic
+	brain := bot.brainFor(discordChat(message))
+	_, err := bot.discord.ChannelMessageSend(message.ChannelID, sayable(brain.GenerateFrom(commandArguments(message.Content))))
+	return err
+}
+
+// Calm sends a new message to the specific chat like Say, sticking to the most common words
+func Calm(update tgbotapi.Update, bot *Bot) error {
+	brain := bot.sampledBrainFor(telegramChat(update.Message.Chat.ID), calmSampling)
+	return bot.sendMessage(update.Message.Chat.ID, sayable(brain.GenerateFrom(update.Message.CommandArguments())))
+}
+
+func calmDiscord(message *discordgo.MessageCreate, bot *Bot) error {
+	brain := bot.sampledBrainFor(discordChat(message), calmSampling)
+	_, err := bot.discord.ChannelMessageSend(message.ChannelID, sayable(brain.GenerateFrom(commandArguments(message.Content))))
 	return err
 }
 
+// Chaos sends a new message to the specific chat like Say, favouring the rarest words
+func Chaos(update tgbotapi.Update, bot *Bot) error {
+	brain := bot.sampledBrainFor(telegramChat(update.Message.Chat.ID), chaosSampling)
+	return bot.sendMessage(update.Message.Chat.ID, sayable(brain.GenerateFrom(update.Message.CommandArguments())))
+}
+
+func chaosDiscord(message *discordgo.MessageCreate, bot *Bot) error {
+	brain := bot.sampledBrainFor(discordChat(message), chaosSampling)
+	_, err := bot.discord.ChannelMessageSend(message.ChannelID, sayable(brain.GenerateFrom(commandArguments(message.Content))))
+	return err
+}
+
+// About sends a new message to the specific chat containing the word given after the command
+func About(update tgbotapi.Update, bot *Bot) error {
+	brain := bot.brainFor(telegramChat(update.Message.Chat.ID))
+	return bot.sendMessage(update.Message.Chat.ID, sayable(brain.GenerateAround(firstWord(update.Message.CommandArguments()))))
+}
+
+func aboutDiscord(message *discordgo.MessageCreate, bot *Bot) error {
+	brain := bot.brainFor(discordChat(message))
+	_, err := bot.discord.ChannelMessageSend(message.ChannelID, sayable(brain.GenerateAround(firstWord(commandArguments(message.Content)))))
+	return err
+}
+
+// Nickname sends a made up word to the specific chat, starting with the word given after the command, if any
+func Nickname(update tgbotapi.Update, bot *Bot) error {
+	brain := bot.brainFor(telegramChat(update.Message.Chat.ID))
+	return bot.sendMessage(update.Message.Chat.ID, makeUpName(brain, firstWord(update.Message.CommandArguments())))
+}
+
+func nicknameDiscord(message *discordgo.MessageCreate, bot *Bot) error {
+	brain := bot.brainFor(discordChat(message))
+	_, err := bot.discord.ChannelMessageSend(message.ChannelID, makeUpName(brain, firstWord(commandArguments(message.Content))))
+	return err
+}
+
+// makeUpName makes up a word from the given brain starting with the given prefix,
+// or with anything if nothing starts with it
+func makeUpName(brain tuskbrain.Generator, prefix string) string {
+	if name := brain.GenerateName(prefix); name != "" {
+		return name
+	}
+	if name := brain.GenerateName(""); name != "" {
+		return name
+	}
+	return "I can't think of a name right now"
+}
+
+// sayable returns the given generated message, or a stand-in if nothing could be generated,
+// as the messaging services refuse to send empty messages
+func sayable(message string) string {
+	if message == "" {
+		return "I can't think of anything to say right now"
+	}
+	return message
+}
+
+// firstWord returns the first word of the given string, or an empty string if there are none
+func firstWord(v string) string {
+	words := stringer.SplitMultiple(v, " \n\t")
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
+
+// commandArguments returns everything past the first word in a command string
+func commandArguments(cmd string) string {
+	cmdParts := stringer.SplitMultiple(cmd, " \n\t")
+	if len(cmdParts) < 2 {
+		return ""
+	}
+	return strings.Join(cmdParts[1:], " ")
+}
+
 // trimCommand removes anything past the first word in a command string
 func trimCommand(cmd string) string {
 	cmdParts := stringer.SplitMultiple(cmd, "@ \n\t")
@@ -9,10 +9,10 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/wallnutkraken/gotuskgo/controlpanel"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
 	"google.golang.org/grpc"
 )
 
@@ -21,6 +21,12 @@ import (
 
 var cliReader = bufio.NewReader(os.Stdin)
 
+// addBatchSize is the amount of messages sent in one AddToDatabase call
+const addBatchSize = 1000
+
+// corpusChunkSize is the size of a chunk of the corpus file sent to BlendCorpus
+const corpusChunkSize = 512 * 1024 // 512 KiB
+
 // Method represents a selectable gRPC method call
 type Method struct {
 	Name        string
@@ -75,6 +81,46 @@ var methods = MethodList{
 			Function:    triggerSendout,
 			Description: "Triggers a message sendout to all available channels",
 		},
+		7: Method{
+			Name:        "RemoveFromDatabase",
+			Function:    removeMessage,
+			Description: "Removes every copy of a message from the database and the GoTuskGo brain",
+		},
+		8: Method{
+			Name:        "GetBrainStats",
+			Function:    getBrainStats,
+			Description: "Shows how big the GoTuskGo brain is, along with its most frequent words",
+		},
+		9: Method{
+			Name:        "ExportBrain",
+			Function:    exportBrain,
+			Description: "Save the GoTuskGo brain, or the part of it around a word, as a DOT or JSON file",
+		},
+		10: Method{
+			Name:        "GetBlocklist",
+			Function:    getBlocklist,
+			Description: "Shows the words and patterns GoTuskGo never learns or says",
+		},
+		11: Method{
+			Name:        "BlockTerms",
+			Function:    blockTerm,
+			Description: "Adds a word or a regular expression to the blocklist",
+		},
+		12: Method{
+			Name:        "UnblockTerms",
+			Function:    unblockTerm,
+			Description: "Removes a word or a regular expression from the blocklist",
+		},
+		13: Method{
+			Name:        "Regenerate",
+			Function:    regenerate,
+			Description: "Makes up a logged message again from its seed, if nothing was fed since",
+		},
+		14: Method{
+			Name:        "BlendCorpus",
+			Function:    blendCorpus,
+			Description: "Blends a plaintext file of messages into the GoTuskGo brain with a weight, without storing them",
+		},
 	},
 }
 var (
@@ -212,27 +258,42 @@ func addMessages(client controlpanel.ControllerClient) {
 		errorExit(err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
-	defer cancel()
 	auth := &controlpanel.AuthCode{
 		Code: *authCode,
 	}
 
-	// Read the file given
-	file, err := ioutil.ReadFile(string(pathBytes))
+	// Read the file given, a batch of lines at a time
+	file, err := os.Open(string(pathBytes))
 	if err != nil {
 		errorExit(err)
 	}
-	// Split it into lines
-	lines := strings.Split(string(file), "\n")
-	messages := &controlpanel.MessageList{
-		Auth:    auth,
-		Message: lines,
+	defer file.Close()
+	batch := []string{}
+	sendBatch := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		messages := &controlpanel.MessageList{
+			Auth:    auth,
+			Message: batch,
+		}
+		if _, err := client.AddToDatabase(ctx, messages); err != nil {
+			errorExit(err)
+		}
+		fmt.Printf("Added [%d] messages\n", len(batch))
+		batch = []string{}
 	}
-	_, err = client.AddToDatabase(ctx, messages)
+	err = gomarkov.ReadMessages(file, "", func(message string) {
+		batch = append(batch, message)
+		if len(batch) == addBatchSize {
+			sendBatch()
+		}
+	})
 	if err != nil {
 		errorExit(err)
 	}
+	if len(batch) != 0 {
+		sendBatch()
+	}
 }
 
 func getDatabase(client controlpanel.ControllerClient) {
@@ -291,3 +352,298 @@ func triggerSendout(client controlpanel.ControllerClient) {
 	}
 	fmt.Println("Done.")
 }
+
+func removeMessage(client controlpanel.ControllerClient) {
+	// Ask for the message
+	fmt.Print("Exact message to remove: ")
+	line, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	auth := &controlpanel.AuthCode{
+		Code: *authCode,
+	}
+	_, err = client.RemoveFromDatabase(ctx, &controlpanel.MessageList{
+		Auth:    auth,
+		Message: []string{string(line)},
+	})
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Println("Done.")
+}
+
+func getBrainStats(client controlpanel.ControllerClient) {
+	// Ask for the amount of words
+	fmt.Print("Amount of most frequent words to show: ")
+	line, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	top, err := strconv.Atoi(string(line))
+	if err != nil {
+		errorExit(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	auth := &controlpanel.AuthCode{
+		Code: *authCode,
+	}
+	stats, err := client.GetBrainStats(ctx, &controlpanel.BrainStatsParams{
+		Auth: auth,
+		Top:  int32(top),
+	})
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Printf("Links: %d\n", stats.Links)
+	fmt.Printf("Transitions: %d\n", stats.Transitions)
+	fmt.Printf("Vocabulary: %d words\n", stats.Vocabulary)
+	fmt.Printf("Average branching factor: %.2f\n", stats.Branching)
+	fmt.Printf("Entropy: %.2f bits\n", stats.Entropy)
+	for i, word := range stats.TopWords {
+		fmt.Printf("[%d] %s: %d\n", i+1, word.Word, word.Count)
+	}
+}
+
+func exportBrain(client controlpanel.ControllerClient) {
+	fmt.Println("Timeouts are disabled for this endpoint due to streaming")
+	auth := &controlpanel.AuthCode{
+		Code: *authCode,
+	}
+
+	// Ask what to export
+	fmt.Print("Format (dot or json): ")
+	format, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Print("Word to export the neighbourhood of (empty for the whole brain): ")
+	word, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	depth := 0
+	if len(word) != 0 {
+		fmt.Print("Depth: ")
+		depthBytes, _, err := cliReader.ReadLine()
+		if err != nil {
+			errorExit(err)
+		}
+		if depth, err = strconv.Atoi(string(depthBytes)); err != nil {
+			errorExit(err)
+		}
+	}
+	fmt.Print("Export filepath: ")
+	pathBytes, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+
+	exportStream, err := client.ExportBrain(context.Background(), &controlpanel.ExportParams{
+		Auth:   auth,
+		Format: string(format),
+		Word:   string(word),
+		Depth:  int32(depth),
+	})
+	if err != nil {
+		errorExit(err)
+	}
+
+	// Open the destination file
+	file, err := os.Create(string(pathBytes))
+	if err != nil {
+		errorExit(err)
+	}
+	defer file.Close()
+
+	// Read the entire stream, write to file as we read
+	for {
+		chunk, err := exportStream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errorExit(err)
+		}
+		if _, err := file.Write(chunk.Content); err != nil {
+			errorExit(err)
+		}
+	}
+	fmt.Printf("Written file in %s\n", string(pathBytes))
+}
+
+func getBlocklist(client controlpanel.ControllerClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	auth := &controlpanel.AuthCode{
+		Code: *authCode,
+	}
+	blocklist, err := client.GetBlocklist(ctx, auth)
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Printf("Attempts: %d\n", blocklist.Attempts)
+	for _, word := range blocklist.Words {
+		fmt.Printf("[word] %s\n", word)
+	}
+	for _, pattern := range blocklist.Patterns {
+		fmt.Printf("[pattern] %s\n", pattern)
+	}
+}
+
+func blockTerm(client controlpanel.ControllerClient) {
+	params := readTerm()
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if _, err := client.BlockTerms(ctx, params); err != nil {
+		errorExit(err)
+	}
+	fmt.Println("Done.")
+}
+
+func unblockTerm(client controlpanel.ControllerClient) {
+	params := readTerm()
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if _, err := client.UnblockTerms(ctx, params); err != nil {
+		errorExit(err)
+	}
+	fmt.Println("Done.")
+}
+
+// readTerm asks for a word or a pattern of the blocklist
+func readTerm() *controlpanel.BlocklistParams {
+	fmt.Print("Word or pattern (w/p): ")
+	kind, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Print("Term: ")
+	term, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	params := &controlpanel.BlocklistParams{
+		Auth: &controlpanel.AuthCode{
+			Code: *authCode,
+		},
+	}
+	switch string(kind) {
+	case "w":
+		params.Words = []string{string(term)}
+	case "p":
+		params.Patterns = []string{string(term)}
+	default:
+		fmt.Println("Invalid selection")
+		os.Exit(1)
+	}
+	return params
+}
+
+func regenerate(client controlpanel.ControllerClient) {
+	// Ask for the seed and the chat from the log line
+	fmt.Print("Seed: ")
+	line, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	seed, err := strconv.ParseInt(string(line), 10, 64)
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Print("Chat ID (empty for the message sent to every chat): ")
+	line, _, err = cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	chatID := int64(0)
+	if len(line) != 0 {
+		if chatID, err = strconv.ParseInt(string(line), 10, 64); err != nil {
+			errorExit(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	generated, err := client.Regenerate(ctx, &controlpanel.RegenerateParams{
+		Auth: &controlpanel.AuthCode{
+			Code: *authCode,
+		},
+		Seed:   seed,
+		ChatID: chatID,
+	})
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Println(generated.Message)
+}
+
+func blendCorpus(client controlpanel.ControllerClient) {
+	fmt.Println("Timeouts are disabled for this endpoint due to streaming")
+	fmt.Println("Corpus file should just be a file with messages, separated by newlines")
+	// Ask for the file and how much it weighs
+	fmt.Print("Corpus filepath: ")
+	pathBytes, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Print("Weight of a corpus message, compared to a received one: ")
+	line, _, err := cliReader.ReadLine()
+	if err != nil {
+		errorExit(err)
+	}
+	weight, err := strconv.ParseFloat(string(line), 64)
+	if err != nil {
+		errorExit(err)
+	}
+
+	file, err := os.Open(string(pathBytes))
+	if err != nil {
+		errorExit(err)
+	}
+	defer file.Close()
+	corpusStream, err := client.BlendCorpus(context.Background())
+	if err != nil {
+		errorExit(err)
+	}
+	// The first chunk says how to read the rest, the server reads the messages as they arrive
+	chunk := &controlpanel.CorpusChunk{
+		Auth: &controlpanel.AuthCode{
+			Code: *authCode,
+		},
+		Weight: weight,
+	}
+	buf := make([]byte, corpusChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n != 0 {
+			chunk.Content = buf[:n]
+			if err := corpusStream.Send(chunk); err != nil {
+				errorExit(err)
+			}
+			chunk = &controlpanel.CorpusChunk{}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errorExit(err)
+		}
+	}
+	if chunk.Auth != nil {
+		// The file was empty, still send what to blend it with
+		if err := corpusStream.Send(chunk); err != nil {
+			errorExit(err)
+		}
+	}
+	result, err := corpusStream.CloseAndRecv()
+	if err != nil {
+		errorExit(err)
+	}
+	fmt.Printf("Blended [%d] messages, with [%d] new transitions\n", result.Messages, result.Added)
+}
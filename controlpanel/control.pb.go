@@ -34,7 +34,7 @@ func (m *AuthCode) Reset()         { *m = AuthCode{} }
 func (m *AuthCode) String() string { return proto.CompactTextString(m) }
 func (*AuthCode) ProtoMessage()    {}
 func (*AuthCode) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{0}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{0}
 }
 func (m *AuthCode) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthCode.Unmarshal(m, b)
@@ -72,7 +72,7 @@ func (m *AppErrors) Reset()         { *m = AppErrors{} }
 func (m *AppErrors) String() string { return proto.CompactTextString(m) }
 func (*AppErrors) ProtoMessage()    {}
 func (*AppErrors) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{1}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{1}
 }
 func (m *AppErrors) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AppErrors.Unmarshal(m, b)
@@ -111,7 +111,7 @@ func (m *ApplicationError) Reset()         { *m = ApplicationError{} }
 func (m *ApplicationError) String() string { return proto.CompactTextString(m) }
 func (*ApplicationError) ProtoMessage()    {}
 func (*ApplicationError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{2}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{2}
 }
 func (m *ApplicationError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApplicationError.Unmarshal(m, b)
@@ -156,7 +156,7 @@ func (m *SerializedData) Reset()         { *m = SerializedData{} }
 func (m *SerializedData) String() string { return proto.CompactTextString(m) }
 func (*SerializedData) ProtoMessage()    {}
 func (*SerializedData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{3}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{3}
 }
 func (m *SerializedData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SerializedData.Unmarshal(m, b)
@@ -195,7 +195,7 @@ func (m *SetConfigParams) Reset()         { *m = SetConfigParams{} }
 func (m *SetConfigParams) String() string { return proto.CompactTextString(m) }
 func (*SetConfigParams) ProtoMessage()    {}
 func (*SetConfigParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{4}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{4}
 }
 func (m *SetConfigParams) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetConfigParams.Unmarshal(m, b)
@@ -241,7 +241,7 @@ func (m *MessageList) Reset()         { *m = MessageList{} }
 func (m *MessageList) String() string { return proto.CompactTextString(m) }
 func (*MessageList) ProtoMessage()    {}
 func (*MessageList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{5}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{5}
 }
 func (m *MessageList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MessageList.Unmarshal(m, b)
@@ -275,6 +275,546 @@ func (m *MessageList) GetMessage() []string {
 	return nil
 }
 
+type BrainStatsParams struct {
+	Auth                 *AuthCode `protobuf:"bytes,1,opt,name=Auth,proto3" json:"Auth,omitempty"`
+	Top                  int32     `protobuf:"varint,2,opt,name=Top,proto3" json:"Top,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *BrainStatsParams) Reset()         { *m = BrainStatsParams{} }
+func (m *BrainStatsParams) String() string { return proto.CompactTextString(m) }
+func (*BrainStatsParams) ProtoMessage()    {}
+func (*BrainStatsParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{6}
+}
+func (m *BrainStatsParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrainStatsParams.Unmarshal(m, b)
+}
+func (m *BrainStatsParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrainStatsParams.Marshal(b, m, deterministic)
+}
+func (dst *BrainStatsParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrainStatsParams.Merge(dst, src)
+}
+func (m *BrainStatsParams) XXX_Size() int {
+	return xxx_messageInfo_BrainStatsParams.Size(m)
+}
+func (m *BrainStatsParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrainStatsParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrainStatsParams proto.InternalMessageInfo
+
+func (m *BrainStatsParams) GetAuth() *AuthCode {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *BrainStatsParams) GetTop() int32 {
+	if m != nil {
+		return m.Top
+	}
+	return 0
+}
+
+type BrainStats struct {
+	Links                int64        `protobuf:"varint,1,opt,name=Links,proto3" json:"Links,omitempty"`
+	Transitions          int64        `protobuf:"varint,2,opt,name=Transitions,proto3" json:"Transitions,omitempty"`
+	Vocabulary           int64        `protobuf:"varint,3,opt,name=Vocabulary,proto3" json:"Vocabulary,omitempty"`
+	Branching            float64      `protobuf:"fixed64,4,opt,name=Branching,proto3" json:"Branching,omitempty"`
+	Entropy              float64      `protobuf:"fixed64,5,opt,name=Entropy,proto3" json:"Entropy,omitempty"`
+	TopWords             []*WordCount `protobuf:"bytes,6,rep,name=TopWords,proto3" json:"TopWords,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BrainStats) Reset()         { *m = BrainStats{} }
+func (m *BrainStats) String() string { return proto.CompactTextString(m) }
+func (*BrainStats) ProtoMessage()    {}
+func (*BrainStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{7}
+}
+func (m *BrainStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrainStats.Unmarshal(m, b)
+}
+func (m *BrainStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrainStats.Marshal(b, m, deterministic)
+}
+func (dst *BrainStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrainStats.Merge(dst, src)
+}
+func (m *BrainStats) XXX_Size() int {
+	return xxx_messageInfo_BrainStats.Size(m)
+}
+func (m *BrainStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrainStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrainStats proto.InternalMessageInfo
+
+func (m *BrainStats) GetLinks() int64 {
+	if m != nil {
+		return m.Links
+	}
+	return 0
+}
+
+func (m *BrainStats) GetTransitions() int64 {
+	if m != nil {
+		return m.Transitions
+	}
+	return 0
+}
+
+func (m *BrainStats) GetVocabulary() int64 {
+	if m != nil {
+		return m.Vocabulary
+	}
+	return 0
+}
+
+func (m *BrainStats) GetBranching() float64 {
+	if m != nil {
+		return m.Branching
+	}
+	return 0
+}
+
+func (m *BrainStats) GetEntropy() float64 {
+	if m != nil {
+		return m.Entropy
+	}
+	return 0
+}
+
+func (m *BrainStats) GetTopWords() []*WordCount {
+	if m != nil {
+		return m.TopWords
+	}
+	return nil
+}
+
+type WordCount struct {
+	Word                 string   `protobuf:"bytes,1,opt,name=Word,proto3" json:"Word,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WordCount) Reset()         { *m = WordCount{} }
+func (m *WordCount) String() string { return proto.CompactTextString(m) }
+func (*WordCount) ProtoMessage()    {}
+func (*WordCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{8}
+}
+func (m *WordCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WordCount.Unmarshal(m, b)
+}
+func (m *WordCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WordCount.Marshal(b, m, deterministic)
+}
+func (dst *WordCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WordCount.Merge(dst, src)
+}
+func (m *WordCount) XXX_Size() int {
+	return xxx_messageInfo_WordCount.Size(m)
+}
+func (m *WordCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_WordCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WordCount proto.InternalMessageInfo
+
+func (m *WordCount) GetWord() string {
+	if m != nil {
+		return m.Word
+	}
+	return ""
+}
+
+func (m *WordCount) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type ExportParams struct {
+	Auth                 *AuthCode `protobuf:"bytes,1,opt,name=Auth,proto3" json:"Auth,omitempty"`
+	Format               string    `protobuf:"bytes,2,opt,name=Format,proto3" json:"Format,omitempty"`
+	Word                 string    `protobuf:"bytes,3,opt,name=Word,proto3" json:"Word,omitempty"`
+	Depth                int32     `protobuf:"varint,4,opt,name=Depth,proto3" json:"Depth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ExportParams) Reset()         { *m = ExportParams{} }
+func (m *ExportParams) String() string { return proto.CompactTextString(m) }
+func (*ExportParams) ProtoMessage()    {}
+func (*ExportParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{9}
+}
+func (m *ExportParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportParams.Unmarshal(m, b)
+}
+func (m *ExportParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportParams.Marshal(b, m, deterministic)
+}
+func (dst *ExportParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportParams.Merge(dst, src)
+}
+func (m *ExportParams) XXX_Size() int {
+	return xxx_messageInfo_ExportParams.Size(m)
+}
+func (m *ExportParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportParams proto.InternalMessageInfo
+
+func (m *ExportParams) GetAuth() *AuthCode {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *ExportParams) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *ExportParams) GetWord() string {
+	if m != nil {
+		return m.Word
+	}
+	return ""
+}
+
+func (m *ExportParams) GetDepth() int32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+type BlocklistParams struct {
+	Auth                 *AuthCode `protobuf:"bytes,1,opt,name=Auth,proto3" json:"Auth,omitempty"`
+	Words                []string  `protobuf:"bytes,2,rep,name=Words,proto3" json:"Words,omitempty"`
+	Patterns             []string  `protobuf:"bytes,3,rep,name=Patterns,proto3" json:"Patterns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *BlocklistParams) Reset()         { *m = BlocklistParams{} }
+func (m *BlocklistParams) String() string { return proto.CompactTextString(m) }
+func (*BlocklistParams) ProtoMessage()    {}
+func (*BlocklistParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{10}
+}
+func (m *BlocklistParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlocklistParams.Unmarshal(m, b)
+}
+func (m *BlocklistParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlocklistParams.Marshal(b, m, deterministic)
+}
+func (dst *BlocklistParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlocklistParams.Merge(dst, src)
+}
+func (m *BlocklistParams) XXX_Size() int {
+	return xxx_messageInfo_BlocklistParams.Size(m)
+}
+func (m *BlocklistParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlocklistParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlocklistParams proto.InternalMessageInfo
+
+func (m *BlocklistParams) GetAuth() *AuthCode {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *BlocklistParams) GetWords() []string {
+	if m != nil {
+		return m.Words
+	}
+	return nil
+}
+
+func (m *BlocklistParams) GetPatterns() []string {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
+type Blocklist struct {
+	Words                []string `protobuf:"bytes,1,rep,name=Words,proto3" json:"Words,omitempty"`
+	Patterns             []string `protobuf:"bytes,2,rep,name=Patterns,proto3" json:"Patterns,omitempty"`
+	Attempts             int32    `protobuf:"varint,3,opt,name=Attempts,proto3" json:"Attempts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Blocklist) Reset()         { *m = Blocklist{} }
+func (m *Blocklist) String() string { return proto.CompactTextString(m) }
+func (*Blocklist) ProtoMessage()    {}
+func (*Blocklist) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{11}
+}
+func (m *Blocklist) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Blocklist.Unmarshal(m, b)
+}
+func (m *Blocklist) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Blocklist.Marshal(b, m, deterministic)
+}
+func (dst *Blocklist) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Blocklist.Merge(dst, src)
+}
+func (m *Blocklist) XXX_Size() int {
+	return xxx_messageInfo_Blocklist.Size(m)
+}
+func (m *Blocklist) XXX_DiscardUnknown() {
+	xxx_messageInfo_Blocklist.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Blocklist proto.InternalMessageInfo
+
+func (m *Blocklist) GetWords() []string {
+	if m != nil {
+		return m.Words
+	}
+	return nil
+}
+
+func (m *Blocklist) GetPatterns() []string {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
+func (m *Blocklist) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+type RegenerateParams struct {
+	Auth                 *AuthCode `protobuf:"bytes,1,opt,name=Auth,proto3" json:"Auth,omitempty"`
+	Seed                 int64     `protobuf:"varint,2,opt,name=Seed,proto3" json:"Seed,omitempty"`
+	ChatID               int64     `protobuf:"varint,3,opt,name=ChatID,proto3" json:"ChatID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RegenerateParams) Reset()         { *m = RegenerateParams{} }
+func (m *RegenerateParams) String() string { return proto.CompactTextString(m) }
+func (*RegenerateParams) ProtoMessage()    {}
+func (*RegenerateParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{12}
+}
+func (m *RegenerateParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegenerateParams.Unmarshal(m, b)
+}
+func (m *RegenerateParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegenerateParams.Marshal(b, m, deterministic)
+}
+func (dst *RegenerateParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegenerateParams.Merge(dst, src)
+}
+func (m *RegenerateParams) XXX_Size() int {
+	return xxx_messageInfo_RegenerateParams.Size(m)
+}
+func (m *RegenerateParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegenerateParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegenerateParams proto.InternalMessageInfo
+
+func (m *RegenerateParams) GetAuth() *AuthCode {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *RegenerateParams) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *RegenerateParams) GetChatID() int64 {
+	if m != nil {
+		return m.ChatID
+	}
+	return 0
+}
+
+type GeneratedMessage struct {
+	Message              string   `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeneratedMessage) Reset()         { *m = GeneratedMessage{} }
+func (m *GeneratedMessage) String() string { return proto.CompactTextString(m) }
+func (*GeneratedMessage) ProtoMessage()    {}
+func (*GeneratedMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{13}
+}
+func (m *GeneratedMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeneratedMessage.Unmarshal(m, b)
+}
+func (m *GeneratedMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeneratedMessage.Marshal(b, m, deterministic)
+}
+func (dst *GeneratedMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeneratedMessage.Merge(dst, src)
+}
+func (m *GeneratedMessage) XXX_Size() int {
+	return xxx_messageInfo_GeneratedMessage.Size(m)
+}
+func (m *GeneratedMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeneratedMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeneratedMessage proto.InternalMessageInfo
+
+func (m *GeneratedMessage) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type CorpusChunk struct {
+	Auth                 *AuthCode `protobuf:"bytes,1,opt,name=Auth,proto3" json:"Auth,omitempty"`
+	Weight               float64   `protobuf:"fixed64,2,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	Delimiter            string    `protobuf:"bytes,3,opt,name=Delimiter,proto3" json:"Delimiter,omitempty"`
+	Content              []byte    `protobuf:"bytes,4,opt,name=Content,proto3" json:"Content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CorpusChunk) Reset()         { *m = CorpusChunk{} }
+func (m *CorpusChunk) String() string { return proto.CompactTextString(m) }
+func (*CorpusChunk) ProtoMessage()    {}
+func (*CorpusChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{14}
+}
+func (m *CorpusChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CorpusChunk.Unmarshal(m, b)
+}
+func (m *CorpusChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CorpusChunk.Marshal(b, m, deterministic)
+}
+func (dst *CorpusChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CorpusChunk.Merge(dst, src)
+}
+func (m *CorpusChunk) XXX_Size() int {
+	return xxx_messageInfo_CorpusChunk.Size(m)
+}
+func (m *CorpusChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_CorpusChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CorpusChunk proto.InternalMessageInfo
+
+func (m *CorpusChunk) GetAuth() *AuthCode {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *CorpusChunk) GetWeight() float64 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+func (m *CorpusChunk) GetDelimiter() string {
+	if m != nil {
+		return m.Delimiter
+	}
+	return ""
+}
+
+func (m *CorpusChunk) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+type BlendResult struct {
+	Messages             int64    `protobuf:"varint,1,opt,name=Messages,proto3" json:"Messages,omitempty"`
+	Added                int64    `protobuf:"varint,2,opt,name=Added,proto3" json:"Added,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlendResult) Reset()         { *m = BlendResult{} }
+func (m *BlendResult) String() string { return proto.CompactTextString(m) }
+func (*BlendResult) ProtoMessage()    {}
+func (*BlendResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{15}
+}
+func (m *BlendResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlendResult.Unmarshal(m, b)
+}
+func (m *BlendResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlendResult.Marshal(b, m, deterministic)
+}
+func (dst *BlendResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlendResult.Merge(dst, src)
+}
+func (m *BlendResult) XXX_Size() int {
+	return xxx_messageInfo_BlendResult.Size(m)
+}
+func (m *BlendResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlendResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlendResult proto.InternalMessageInfo
+
+func (m *BlendResult) GetMessages() int64 {
+	if m != nil {
+		return m.Messages
+	}
+	return 0
+}
+
+func (m *BlendResult) GetAdded() int64 {
+	if m != nil {
+		return m.Added
+	}
+	return 0
+}
+
 type Empty struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -285,7 +825,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_control_1cbb2a8270886f94, []int{6}
+	return fileDescriptor_control_1cf5c8a332d3596e, []int{16}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -312,6 +852,16 @@ func init() {
 	proto.RegisterType((*SerializedData)(nil), "controlpanel.SerializedData")
 	proto.RegisterType((*SetConfigParams)(nil), "controlpanel.SetConfigParams")
 	proto.RegisterType((*MessageList)(nil), "controlpanel.MessageList")
+	proto.RegisterType((*BrainStatsParams)(nil), "controlpanel.BrainStatsParams")
+	proto.RegisterType((*BrainStats)(nil), "controlpanel.BrainStats")
+	proto.RegisterType((*WordCount)(nil), "controlpanel.WordCount")
+	proto.RegisterType((*ExportParams)(nil), "controlpanel.ExportParams")
+	proto.RegisterType((*BlocklistParams)(nil), "controlpanel.BlocklistParams")
+	proto.RegisterType((*Blocklist)(nil), "controlpanel.Blocklist")
+	proto.RegisterType((*RegenerateParams)(nil), "controlpanel.RegenerateParams")
+	proto.RegisterType((*GeneratedMessage)(nil), "controlpanel.GeneratedMessage")
+	proto.RegisterType((*CorpusChunk)(nil), "controlpanel.CorpusChunk")
+	proto.RegisterType((*BlendResult)(nil), "controlpanel.BlendResult")
 	proto.RegisterType((*Empty)(nil), "controlpanel.Empty")
 }
 
@@ -333,6 +883,14 @@ type ControllerClient interface {
 	GetDatabase(ctx context.Context, in *AuthCode, opts ...grpc.CallOption) (Controller_GetDatabaseClient, error)
 	AddToDatabase(ctx context.Context, in *MessageList, opts ...grpc.CallOption) (*Empty, error)
 	TriggerSendout(ctx context.Context, in *AuthCode, opts ...grpc.CallOption) (*Empty, error)
+	RemoveFromDatabase(ctx context.Context, in *MessageList, opts ...grpc.CallOption) (*Empty, error)
+	GetBrainStats(ctx context.Context, in *BrainStatsParams, opts ...grpc.CallOption) (*BrainStats, error)
+	ExportBrain(ctx context.Context, in *ExportParams, opts ...grpc.CallOption) (Controller_ExportBrainClient, error)
+	GetBlocklist(ctx context.Context, in *AuthCode, opts ...grpc.CallOption) (*Blocklist, error)
+	BlockTerms(ctx context.Context, in *BlocklistParams, opts ...grpc.CallOption) (*Empty, error)
+	UnblockTerms(ctx context.Context, in *BlocklistParams, opts ...grpc.CallOption) (*Empty, error)
+	Regenerate(ctx context.Context, in *RegenerateParams, opts ...grpc.CallOption) (*GeneratedMessage, error)
+	BlendCorpus(ctx context.Context, opts ...grpc.CallOption) (Controller_BlendCorpusClient, error)
 }
 
 type controllerClient struct {
@@ -420,6 +978,126 @@ func (c *controllerClient) TriggerSendout(ctx context.Context, in *AuthCode, opt
 	return out, nil
 }
 
+func (c *controllerClient) RemoveFromDatabase(ctx context.Context, in *MessageList, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/RemoveFromDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GetBrainStats(ctx context.Context, in *BrainStatsParams, opts ...grpc.CallOption) (*BrainStats, error) {
+	out := new(BrainStats)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/GetBrainStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ExportBrain(ctx context.Context, in *ExportParams, opts ...grpc.CallOption) (Controller_ExportBrainClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Controller_serviceDesc.Streams[1], "/controlpanel.Controller/ExportBrain", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerExportBrainClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Controller_ExportBrainClient interface {
+	Recv() (*SerializedData, error)
+	grpc.ClientStream
+}
+
+type controllerExportBrainClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerExportBrainClient) Recv() (*SerializedData, error) {
+	m := new(SerializedData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controllerClient) GetBlocklist(ctx context.Context, in *AuthCode, opts ...grpc.CallOption) (*Blocklist, error) {
+	out := new(Blocklist)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/GetBlocklist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) BlockTerms(ctx context.Context, in *BlocklistParams, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/BlockTerms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) UnblockTerms(ctx context.Context, in *BlocklistParams, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/UnblockTerms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) Regenerate(ctx context.Context, in *RegenerateParams, opts ...grpc.CallOption) (*GeneratedMessage, error) {
+	out := new(GeneratedMessage)
+	err := c.cc.Invoke(ctx, "/controlpanel.Controller/Regenerate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) BlendCorpus(ctx context.Context, opts ...grpc.CallOption) (Controller_BlendCorpusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Controller_serviceDesc.Streams[2], "/controlpanel.Controller/BlendCorpus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerBlendCorpusClient{stream}
+	return x, nil
+}
+
+type Controller_BlendCorpusClient interface {
+	Send(*CorpusChunk) error
+	CloseAndRecv() (*BlendResult, error)
+	grpc.ClientStream
+}
+
+type controllerBlendCorpusClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerBlendCorpusClient) Send(m *CorpusChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *controllerBlendCorpusClient) CloseAndRecv() (*BlendResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BlendResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ControllerServer is the server API for Controller service.
 type ControllerServer interface {
 	GetApplicationErrors(context.Context, *AuthCode) (*AppErrors, error)
@@ -428,6 +1106,14 @@ type ControllerServer interface {
 	GetDatabase(*AuthCode, Controller_GetDatabaseServer) error
 	AddToDatabase(context.Context, *MessageList) (*Empty, error)
 	TriggerSendout(context.Context, *AuthCode) (*Empty, error)
+	RemoveFromDatabase(context.Context, *MessageList) (*Empty, error)
+	GetBrainStats(context.Context, *BrainStatsParams) (*BrainStats, error)
+	ExportBrain(*ExportParams, Controller_ExportBrainServer) error
+	GetBlocklist(context.Context, *AuthCode) (*Blocklist, error)
+	BlockTerms(context.Context, *BlocklistParams) (*Empty, error)
+	UnblockTerms(context.Context, *BlocklistParams) (*Empty, error)
+	Regenerate(context.Context, *RegenerateParams) (*GeneratedMessage, error)
+	BlendCorpus(Controller_BlendCorpusServer) error
 }
 
 func RegisterControllerServer(s *grpc.Server, srv ControllerServer) {
@@ -545,6 +1231,161 @@ func _Controller_TriggerSendout_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Controller_RemoveFromDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).RemoveFromDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/RemoveFromDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).RemoveFromDatabase(ctx, req.(*MessageList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GetBrainStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrainStatsParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GetBrainStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/GetBrainStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GetBrainStats(ctx, req.(*BrainStatsParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ExportBrain_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControllerServer).ExportBrain(m, &controllerExportBrainServer{stream})
+}
+
+type Controller_ExportBrainServer interface {
+	Send(*SerializedData) error
+	grpc.ServerStream
+}
+
+type controllerExportBrainServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerExportBrainServer) Send(m *SerializedData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Controller_GetBlocklist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GetBlocklist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/GetBlocklist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GetBlocklist(ctx, req.(*AuthCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_BlockTerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlocklistParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).BlockTerms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/BlockTerms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).BlockTerms(ctx, req.(*BlocklistParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_UnblockTerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlocklistParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).UnblockTerms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/UnblockTerms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).UnblockTerms(ctx, req.(*BlocklistParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_Regenerate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).Regenerate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controlpanel.Controller/Regenerate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).Regenerate(ctx, req.(*RegenerateParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_BlendCorpus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ControllerServer).BlendCorpus(&controllerBlendCorpusServer{stream})
+}
+
+type Controller_BlendCorpusServer interface {
+	SendAndClose(*BlendResult) error
+	Recv() (*CorpusChunk, error)
+	grpc.ServerStream
+}
+
+type controllerBlendCorpusServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerBlendCorpusServer) SendAndClose(m *BlendResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *controllerBlendCorpusServer) Recv() (*CorpusChunk, error) {
+	m := new(CorpusChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Controller_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controlpanel.Controller",
 	HandlerType: (*ControllerServer)(nil),
@@ -569,6 +1410,30 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TriggerSendout",
 			Handler:    _Controller_TriggerSendout_Handler,
 		},
+		{
+			MethodName: "RemoveFromDatabase",
+			Handler:    _Controller_RemoveFromDatabase_Handler,
+		},
+		{
+			MethodName: "GetBrainStats",
+			Handler:    _Controller_GetBrainStats_Handler,
+		},
+		{
+			MethodName: "GetBlocklist",
+			Handler:    _Controller_GetBlocklist_Handler,
+		},
+		{
+			MethodName: "BlockTerms",
+			Handler:    _Controller_BlockTerms_Handler,
+		},
+		{
+			MethodName: "UnblockTerms",
+			Handler:    _Controller_UnblockTerms_Handler,
+		},
+		{
+			MethodName: "Regenerate",
+			Handler:    _Controller_Regenerate_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -576,37 +1441,76 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Controller_GetDatabase_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExportBrain",
+			Handler:       _Controller_ExportBrain_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BlendCorpus",
+			Handler:       _Controller_BlendCorpus_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "control.proto",
 }
 
-func init() { proto.RegisterFile("control.proto", fileDescriptor_control_1cbb2a8270886f94) }
-
-var fileDescriptor_control_1cbb2a8270886f94 = []byte{
-	// 385 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0x5f, 0x6b, 0xe2, 0x40,
-	0x10, 0x27, 0x46, 0xcf, 0xcb, 0x44, 0xbd, 0x63, 0x4f, 0xee, 0x72, 0x72, 0x27, 0x21, 0x4f, 0xc1,
-	0x07, 0x91, 0xdc, 0x3d, 0xde, 0x21, 0x21, 0x95, 0xbc, 0xb4, 0x50, 0x12, 0xfb, 0x01, 0x56, 0xb3,
-	0x4d, 0x17, 0xe2, 0x6e, 0xd8, 0xac, 0xd0, 0xf6, 0xd3, 0xf4, 0xa3, 0x96, 0xac, 0xd1, 0x9a, 0x54,
-	0x0b, 0xed, 0x53, 0x66, 0xd8, 0xdf, 0xbf, 0x99, 0x21, 0xd0, 0x5f, 0x73, 0x26, 0x05, 0xcf, 0xa6,
-	0xb9, 0xe0, 0x92, 0xa3, 0x5e, 0xd5, 0xe6, 0x98, 0x91, 0xcc, 0x19, 0xc3, 0x67, 0x7f, 0x2b, 0xef,
-	0x02, 0x9e, 0x10, 0x84, 0xa0, 0x5d, 0x7e, 0x2d, 0xcd, 0xd6, 0x5c, 0x23, 0x52, 0xb5, 0xe3, 0x83,
-	0xe1, 0xe7, 0xf9, 0x42, 0x08, 0x2e, 0x0a, 0xf4, 0x17, 0x3a, 0xaa, 0xb2, 0x34, 0x5b, 0x77, 0x4d,
-	0x6f, 0x3c, 0x3d, 0x96, 0x9a, 0xfa, 0x79, 0x9e, 0xd1, 0x35, 0x96, 0x94, 0x33, 0x85, 0x8a, 0x76,
-	0x60, 0xe7, 0x1f, 0x7c, 0x6d, 0x3e, 0xa1, 0xe1, 0x8b, 0x52, 0xe9, 0xb5, 0x6b, 0xca, 0x00, 0x37,
-	0x8c, 0xde, 0x5b, 0x2d, 0x5b, 0x73, 0xf5, 0x48, 0xd5, 0xce, 0x04, 0x06, 0x31, 0x11, 0x14, 0x67,
-	0xf4, 0x91, 0x24, 0x17, 0x58, 0x62, 0x64, 0x41, 0x37, 0xe0, 0x4c, 0x12, 0x26, 0x15, 0xbb, 0x17,
-	0xed, 0x5b, 0x87, 0xc3, 0x97, 0x98, 0xc8, 0x80, 0xb3, 0x5b, 0x9a, 0x5e, 0x63, 0x81, 0x37, 0x05,
-	0x9a, 0x40, 0xbb, 0x9c, 0x4f, 0x21, 0x4d, 0xef, 0x7b, 0x23, 0x71, 0x35, 0x79, 0xa4, 0x30, 0x68,
-	0x06, 0xed, 0xd2, 0x40, 0xd9, 0x9b, 0xde, 0xaf, 0x3a, 0xb6, 0x1e, 0x22, 0x52, 0x48, 0x27, 0x06,
-	0xf3, 0x8a, 0x14, 0x05, 0x4e, 0xc9, 0x25, 0x2d, 0xe4, 0xbb, 0xcc, 0x2c, 0xe8, 0x56, 0x54, 0xab,
-	0x65, 0xeb, 0xae, 0x11, 0xed, 0x5b, 0xa7, 0x0b, 0x9d, 0xc5, 0x26, 0x97, 0x0f, 0xde, 0x93, 0x0e,
-	0x10, 0xec, 0x24, 0x32, 0x22, 0x50, 0x08, 0xc3, 0x90, 0xc8, 0xe6, 0x2a, 0x0b, 0x74, 0xc6, 0x67,
-	0xf4, 0xe3, 0xd5, 0x79, 0x2a, 0xc2, 0x1c, 0x8c, 0xc3, 0x9a, 0xd0, 0xef, 0xe6, 0x98, 0xb5, 0xfd,
-	0x8d, 0xbe, 0xd5, 0x9f, 0x55, 0x30, 0xe4, 0x83, 0x11, 0x1e, 0x04, 0xce, 0xd9, 0xbf, 0xb9, 0x3f,
-	0xb4, 0x00, 0x33, 0x24, 0xb2, 0x2c, 0x57, 0xb8, 0x20, 0x1f, 0x13, 0x99, 0x69, 0x68, 0x0e, 0x7d,
-	0x3f, 0x49, 0x96, 0xfc, 0x20, 0xf4, 0xb3, 0x4e, 0x38, 0xba, 0xce, 0xe9, 0x51, 0xfe, 0xc3, 0x60,
-	0x29, 0x68, 0x9a, 0x12, 0x11, 0x13, 0x96, 0xf0, 0xad, 0x3c, 0x1b, 0xe5, 0x14, 0x7d, 0xf5, 0x49,
-	0xfd, 0x53, 0x7f, 0x9e, 0x03, 0x00, 0x00, 0xff, 0xff, 0xda, 0x1f, 0x7a, 0x33, 0x64, 0x03, 0x00,
-	0x00,
+func init() { proto.RegisterFile("control.proto", fileDescriptor_control_1cf5c8a332d3596e) }
+
+var fileDescriptor_control_1cf5c8a332d3596e = []byte{
+	// 854 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xdb, 0x6e, 0xdb, 0x36,
+	0x18, 0x86, 0x62, 0x3b, 0x89, 0x7e, 0x39, 0x9d, 0xc1, 0x05, 0xad, 0x6a, 0x74, 0x81, 0xa1, 0x2b,
+	0xa3, 0x18, 0x82, 0x22, 0xdd, 0xee, 0x36, 0x64, 0x3e, 0xd5, 0x08, 0x90, 0x01, 0x01, 0xed, 0xae,
+	0xd8, 0x25, 0x6d, 0xfd, 0xb3, 0xb5, 0x48, 0xa4, 0x40, 0xd2, 0x43, 0x33, 0xec, 0x05, 0xf6, 0x78,
+	0xbb, 0xdb, 0xe3, 0x0c, 0xa4, 0x64, 0x59, 0x52, 0xe3, 0xa1, 0x71, 0xaf, 0xcc, 0x8f, 0xff, 0xf9,
+	0xc0, 0x4f, 0x86, 0xb3, 0xa5, 0xe0, 0x5a, 0x8a, 0xf8, 0x32, 0x95, 0x42, 0x0b, 0xd2, 0xce, 0x61,
+	0xca, 0x38, 0xc6, 0xc1, 0x05, 0x9c, 0x0e, 0x36, 0x7a, 0x3d, 0x12, 0x21, 0x12, 0x02, 0x4d, 0xf3,
+	0xeb, 0x3b, 0x3d, 0xa7, 0xef, 0x52, 0x7b, 0x0e, 0x06, 0xe0, 0x0e, 0xd2, 0x74, 0x22, 0xa5, 0x90,
+	0x8a, 0x7c, 0x07, 0x2d, 0x7b, 0xf2, 0x9d, 0x5e, 0xa3, 0xef, 0x5d, 0x5d, 0x5c, 0x96, 0x5d, 0x5d,
+	0x0e, 0xd2, 0x34, 0x8e, 0x96, 0x4c, 0x47, 0x82, 0x5b, 0x2d, 0x9a, 0x29, 0x07, 0x3f, 0x40, 0xa7,
+	0x2e, 0x22, 0xe7, 0x3b, 0x4f, 0x26, 0x56, 0x06, 0x4c, 0x02, 0xef, 0x79, 0xf4, 0xd1, 0x3f, 0xea,
+	0x39, 0xfd, 0x06, 0xb5, 0xe7, 0xe0, 0x35, 0x3c, 0x9b, 0xa1, 0x8c, 0x58, 0x1c, 0xfd, 0x89, 0xe1,
+	0x98, 0x69, 0x46, 0x7c, 0x38, 0x19, 0x09, 0xae, 0x91, 0x6b, 0x6b, 0xdd, 0xa6, 0x5b, 0x18, 0x08,
+	0xf8, 0x6a, 0x86, 0x7a, 0x24, 0xf8, 0x6f, 0xd1, 0xea, 0x8e, 0x49, 0x96, 0x28, 0xf2, 0x1a, 0x9a,
+	0xa6, 0x3e, 0xab, 0xe9, 0x5d, 0x3d, 0xaf, 0x65, 0x9c, 0x57, 0x4e, 0xad, 0x0e, 0x79, 0x03, 0x4d,
+	0x13, 0xc0, 0x86, 0xf7, 0xae, 0x5e, 0x55, 0x75, 0xab, 0x49, 0x50, 0xab, 0x19, 0xcc, 0xc0, 0xfb,
+	0x19, 0x95, 0x62, 0x2b, 0xbc, 0x8d, 0x94, 0x7e, 0x52, 0x30, 0x1f, 0x4e, 0x72, 0x53, 0xff, 0xa8,
+	0xd7, 0xe8, 0xbb, 0x74, 0x0b, 0x83, 0x3b, 0xe8, 0x0c, 0x25, 0x8b, 0xf8, 0x4c, 0x33, 0xad, 0x0e,
+	0x28, 0xa3, 0x03, 0x8d, 0xb9, 0x48, 0x6d, 0x15, 0x2d, 0x6a, 0x8e, 0xc1, 0x3f, 0x0e, 0xc0, 0xce,
+	0xa5, 0x69, 0xfe, 0x6d, 0xc4, 0xef, 0x95, 0xf5, 0xd6, 0xa0, 0x19, 0x20, 0x3d, 0xf0, 0xe6, 0x92,
+	0x71, 0x15, 0x99, 0x29, 0xa9, 0x7c, 0x06, 0xe5, 0x2b, 0x72, 0x01, 0xf0, 0x8b, 0x58, 0xb2, 0xc5,
+	0x26, 0x66, 0xf2, 0xc1, 0x6f, 0x58, 0x85, 0xd2, 0x0d, 0x79, 0x05, 0xee, 0x50, 0x32, 0xbe, 0x5c,
+	0x47, 0x7c, 0xe5, 0x37, 0x7b, 0x4e, 0xdf, 0xa1, 0xbb, 0x0b, 0x53, 0xf0, 0xc4, 0x24, 0x9d, 0x3e,
+	0xf8, 0x2d, 0x2b, 0xdb, 0x42, 0xf2, 0x16, 0x4e, 0xe7, 0x22, 0xfd, 0x20, 0x64, 0xa8, 0xfc, 0x63,
+	0xbb, 0x59, 0x2f, 0xaa, 0x05, 0x1a, 0xd1, 0x48, 0x6c, 0xb8, 0xa6, 0x85, 0x62, 0xf0, 0x3d, 0xb8,
+	0xc5, 0xb5, 0x59, 0x1c, 0x03, 0xb6, 0x9b, 0x6b, 0xce, 0xa6, 0x4a, 0x2b, 0xcc, 0x2b, 0xc9, 0x40,
+	0xf0, 0x17, 0xb4, 0x27, 0x1f, 0x53, 0x21, 0xf5, 0x01, 0x8d, 0x7d, 0x0e, 0xc7, 0xef, 0x84, 0x4c,
+	0x58, 0xe6, 0xd2, 0xa5, 0x39, 0x2a, 0xa2, 0x37, 0xaa, 0xd1, 0xc7, 0x98, 0xea, 0xb5, 0xed, 0x43,
+	0x8b, 0x66, 0xc0, 0x2c, 0xe8, 0x30, 0x16, 0xcb, 0xfb, 0x38, 0x52, 0x87, 0x24, 0x70, 0x0e, 0xad,
+	0xac, 0x4b, 0xd9, 0xc6, 0x64, 0x80, 0x74, 0xe1, 0xf4, 0x8e, 0x69, 0x8d, 0x92, 0x2b, 0xbf, 0x61,
+	0x05, 0x05, 0x0e, 0x7e, 0x05, 0xb7, 0x08, 0xb8, 0x33, 0x77, 0xf6, 0x99, 0x1f, 0x55, 0xcd, 0x8d,
+	0x6c, 0xa0, 0x35, 0x26, 0xa9, 0x56, 0xb6, 0xba, 0x16, 0x2d, 0x70, 0xf0, 0x3b, 0x74, 0x28, 0xae,
+	0x90, 0xa3, 0x64, 0x1a, 0x0f, 0x28, 0x86, 0x40, 0x73, 0x86, 0x18, 0x6e, 0x1f, 0xbb, 0x39, 0x9b,
+	0x0e, 0x8f, 0xd6, 0x4c, 0xdf, 0x8c, 0xf3, 0xed, 0xca, 0x51, 0xf0, 0x2d, 0x74, 0xa6, 0x79, 0xa4,
+	0x30, 0x7f, 0x26, 0xe5, 0x07, 0x94, 0x8d, 0xbd, 0x78, 0x40, 0x7f, 0x3b, 0xe0, 0x8d, 0x84, 0x4c,
+	0x37, 0x6a, 0xb4, 0xde, 0xf0, 0xfb, 0xa7, 0xce, 0xf8, 0x03, 0x46, 0xab, 0x75, 0x36, 0x63, 0x87,
+	0xe6, 0xc8, 0xec, 0xf6, 0x18, 0xe3, 0x28, 0x89, 0x34, 0xca, 0x7c, 0xd0, 0xbb, 0x8b, 0x32, 0x25,
+	0x35, 0xab, 0x94, 0x74, 0x0d, 0xde, 0x30, 0x46, 0x1e, 0x52, 0x54, 0x9b, 0x58, 0x9b, 0x86, 0xe6,
+	0x59, 0x6e, 0x5f, 0x5f, 0x81, 0xcd, 0x78, 0x06, 0x61, 0x58, 0x74, 0x24, 0x03, 0xc1, 0x09, 0xb4,
+	0x26, 0x49, 0xaa, 0x1f, 0xae, 0xfe, 0x3d, 0x01, 0x18, 0x65, 0x99, 0xc7, 0x28, 0xc9, 0x14, 0xce,
+	0xa7, 0xa8, 0xeb, 0xc4, 0xaa, 0xc8, 0x9e, 0xf2, 0xba, 0x2f, 0x3e, 0x21, 0xeb, 0xdc, 0xe0, 0x1a,
+	0xdc, 0x82, 0x34, 0xc9, 0x37, 0x75, 0xd2, 0xab, 0xb0, 0x69, 0xf7, 0xeb, 0xaa, 0xd8, 0x26, 0x46,
+	0x06, 0xe0, 0x4e, 0x0b, 0x07, 0xfb, 0xc2, 0xff, 0x2f, 0x9b, 0x92, 0x09, 0x78, 0x53, 0xd4, 0xe6,
+	0xb8, 0x60, 0x0a, 0x0f, 0x73, 0xf2, 0xc6, 0x21, 0xd7, 0x70, 0x36, 0x08, 0xc3, 0xb9, 0x28, 0x1c,
+	0xbd, 0xac, 0x1a, 0x94, 0xb8, 0xfa, 0xf1, 0x52, 0x7e, 0x84, 0x67, 0x73, 0x19, 0xad, 0x56, 0x28,
+	0x67, 0xc8, 0x43, 0xb1, 0xd1, 0x7b, 0x53, 0x79, 0xd4, 0x7c, 0x0c, 0x84, 0x62, 0x22, 0xfe, 0xc0,
+	0x77, 0x52, 0x24, 0x07, 0x27, 0x71, 0x03, 0x67, 0x53, 0xd4, 0x25, 0xbe, 0xae, 0x7d, 0x67, 0xeb,
+	0x1f, 0x87, 0xae, 0xbf, 0x4f, 0x4e, 0x6e, 0xc0, 0xcb, 0xd8, 0xce, 0xde, 0x91, 0x6e, 0x2d, 0x5c,
+	0x89, 0x08, 0x3f, 0xa3, 0xb7, 0x6d, 0x93, 0x55, 0x41, 0x26, 0x9f, 0xb9, 0x67, 0x3b, 0x83, 0x9f,
+	0x00, 0x2c, 0x98, 0xa3, 0x4c, 0x54, 0x7d, 0xd1, 0x6a, 0xac, 0xf8, 0x78, 0x63, 0x86, 0xd0, 0x7e,
+	0xcf, 0x17, 0x5f, 0xe6, 0xe3, 0x16, 0x60, 0xc7, 0x5a, 0xf5, 0xce, 0xd6, 0xf9, 0xac, 0x5b, 0x93,
+	0x7f, 0xc2, 0x41, 0x93, 0xfc, 0x75, 0x67, 0x6c, 0x53, 0x9f, 0x74, 0x89, 0x83, 0xba, 0x2f, 0xeb,
+	0xb9, 0x16, 0x9c, 0xd0, 0x77, 0x16, 0xc7, 0xf6, 0x9f, 0xd9, 0xdb, 0xff, 0x06, 0x00, 0x23, 0xde,
+	0x04, 0xeb, 0xaa, 0x09, 0x00, 0x00,
 }
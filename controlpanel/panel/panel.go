@@ -2,10 +2,12 @@
 package panel
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/pkg/errors"
 	"github.com/wallnutkraken/gotuskgo/controlpanel"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/serial"
 	"google.golang.org/grpc"
 	"net"
@@ -113,16 +115,13 @@ func (p *Panel) SetConfig(ctx context.Context, params *controlpanel.SetConfigPar
 		return nil, errors.Wrap(err, "json")
 	}
 
-	// Save it to file
-	if err := settings.Save(config); err != nil {
-		return nil, errors.Wrap(err, "save")
+	// Run the setting change propogations, which save it to file once it's accepted
+	if err := p.srv.SetSettings(config); err != nil {
+		return nil, err
 	}
-
 	// And propogate the changes
 	p.config = config.GRPC
-	// Run the setting change propogations
-	err := p.srv.SetSettings(config)
-	return &controlpanel.Empty{}, err
+	return &controlpanel.Empty{}, nil
 }
 
 // AddToDatabase provides a gRPC endpoint for adding a payload of messages to the database
@@ -135,6 +134,42 @@ func (p *Panel) AddToDatabase(ctx context.Context, messages *controlpanel.Messag
 	return &controlpanel.Empty{}, err
 }
 
+// RemoveFromDatabase provides a gRPC endpoint for removing messages from the database and the brain
+func (p *Panel) RemoveFromDatabase(ctx context.Context, messages *controlpanel.MessageList) (*controlpanel.Empty, error) {
+	if messages.Auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	err := p.srv.RemoveMessages(messages.Message)
+	return &controlpanel.Empty{}, err
+}
+
+// GetBrainStats is the gRPC endpoint for getting the statistics of the markov chain,
+// along with the given amount of most frequent words
+func (p *Panel) GetBrainStats(ctx context.Context, params *controlpanel.BrainStatsParams) (*controlpanel.BrainStats, error) {
+	if params.Auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	stats := p.srv.BrainStats(int(params.Top))
+	// Convert the top words to their gRPC counterpart
+	topWords := []*controlpanel.WordCount{}
+	for _, word := range stats.TopWords {
+		topWords = append(topWords, &controlpanel.WordCount{
+			Word:  word.Word,
+			Count: int64(word.Count),
+		})
+	}
+	return &controlpanel.BrainStats{
+		Links:       int64(stats.Links),
+		Transitions: int64(stats.Transitions),
+		Vocabulary:  int64(stats.Vocabulary),
+		Branching:   stats.Branching,
+		Entropy:     stats.Entropy,
+		TopWords:    topWords,
+	}, nil
+}
+
 // GetDatabase is the gRPC endpoint for getting a gzipped backup of the database messages (not chat IDs)
 func (p *Panel) GetDatabase(auth *controlpanel.AuthCode, respStream controlpanel.Controller_GetDatabaseServer) error {
 	if auth.Code != p.config.AuthCode {
@@ -152,6 +187,122 @@ func (p *Panel) GetDatabase(auth *controlpanel.AuthCode, respStream controlpanel
 	if err != nil {
 		return errors.Wrap(err, "serial")
 	}
+	return sendChunks(rawData, respStream)
+}
+
+// ExportBrain is the gRPC endpoint for getting the markov chain in the DOT or JSON format, either all
+// of it or just the neighbourhood of the given word up to the given depth
+func (p *Panel) ExportBrain(params *controlpanel.ExportParams, respStream controlpanel.Controller_ExportBrainServer) error {
+	if params.Auth.Code != p.config.AuthCode {
+		return ErrBadAuthCode
+	}
+
+	// Export it all first, so that the brain isn't held up by a slow connection
+	export := &bytes.Buffer{}
+	if err := p.srv.ExportBrain(export, gomarkov.ExportFormat(params.Format), params.Word, int(params.Depth)); err != nil {
+		return errors.WithMessage(err, "Export Error")
+	}
+	return sendChunks(export.Bytes(), respStream)
+}
+
+// GetBlocklist is the gRPC endpoint for getting the blocked words and patterns of the content filter
+func (p *Panel) GetBlocklist(ctx context.Context, auth *controlpanel.AuthCode) (*controlpanel.Blocklist, error) {
+	if auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	blocklist := p.srv.Blocklist()
+	return &controlpanel.Blocklist{
+		Words:    blocklist.Words,
+		Patterns: blocklist.Patterns,
+		Attempts: int32(blocklist.Attempts),
+	}, nil
+}
+
+// BlockTerms is the gRPC endpoint for adding words and patterns to the blocklist. Messages containing them
+// are no longer stored, and generated messages containing them are generated again.
+func (p *Panel) BlockTerms(ctx context.Context, params *controlpanel.BlocklistParams) (*controlpanel.Empty, error) {
+	if params.Auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	err := p.srv.BlockTerms(params.Words, params.Patterns)
+	return &controlpanel.Empty{}, err
+}
+
+// UnblockTerms is the gRPC endpoint for removing words and patterns from the blocklist
+func (p *Panel) UnblockTerms(ctx context.Context, params *controlpanel.BlocklistParams) (*controlpanel.Empty, error) {
+	if params.Auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	err := p.srv.UnblockTerms(params.Words, params.Patterns)
+	return &controlpanel.Empty{}, err
+}
+
+// Regenerate is the gRPC endpoint for making up a message sent out earlier again, from the seed it was
+// logged with. It's only the same message as long as the brain wasn't fed since.
+func (p *Panel) Regenerate(ctx context.Context, params *controlpanel.RegenerateParams) (*controlpanel.GeneratedMessage, error) {
+	if params.Auth.Code != p.config.AuthCode {
+		return nil, ErrBadAuthCode
+	}
+
+	return &controlpanel.GeneratedMessage{
+		Message: p.srv.Regenerate(params.ChatID, params.Seed),
+	}, nil
+}
+
+// BlendCorpus is the gRPC endpoint for blending a curated corpus into the brain with a weight,
+// without storing its messages in the database. The corpus is streamed in chunks, the first of which
+// has the authentication code, the weight and the delimiter of the messages.
+func (p *Panel) BlendCorpus(reqStream controlpanel.Controller_BlendCorpusServer) error {
+	first, err := reqStream.Recv()
+	if err != nil {
+		return errors.Wrap(err, "Recv")
+	}
+	if first.Auth == nil || first.Auth.Code != p.config.AuthCode {
+		return ErrBadAuthCode
+	}
+
+	corpus := &chunkReader{stream: reqStream, chunk: first.Content}
+	messages, added, err := p.srv.BlendCorpus(corpus, first.Delimiter, first.Weight)
+	if err != nil {
+		return err
+	}
+	return reqStream.SendAndClose(&controlpanel.BlendResult{
+		Messages: int64(messages),
+		Added:    int64(added),
+	})
+}
+
+// chunkReader reads the content of a stream of CorpusChunks, receiving the next chunk once the
+// current one is read
+type chunkReader struct {
+	stream controlpanel.Controller_BlendCorpusServer
+	chunk  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		next, err := r.stream.Recv()
+		if err != nil {
+			// io.EOF once the client is done sending
+			return 0, err
+		}
+		r.chunk = next.Content
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// dataSender is a gRPC stream of SerializedData
+type dataSender interface {
+	Send(*controlpanel.SerializedData) error
+}
+
+// sendChunks sends the given data to the stream in chunks of ChunkSize
+func sendChunks(rawData []byte, respStream dataSender) error {
 	// Check if rawData is smaller or equal to ChunkSize, if so, just send it and return
 	if len(rawData) <= ChunkSize {
 		err := respStream.Send(&controlpanel.SerializedData{
@@ -0,0 +1,64 @@
+package gomarkov
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// MaxMessageSize is the largest message ReadMessages can read, longer messages make it fail with bufio.ErrTooLong
+const MaxMessageSize = 1024 * 1024
+
+// ReadMessages reads the given Reader as messages separated by delimiter, calling feed with every message
+// that isn't empty. Only one message is kept in memory at a time, so files of any size can be read.
+// An empty delimiter separates messages with newlines, in which case a trailing carriage return is dropped.
+func ReadMessages(r io.Reader, delimiter string, feed func(message string)) error {
+	crlf := delimiter == ""
+	if crlf {
+		delimiter = "\n"
+	}
+	delim := []byte(delimiter)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxMessageSize)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.Index(data, delim); i >= 0 {
+			return i + len(delim), data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			// The last message doesn't need a delimiter after it
+			return len(data), data, nil
+		}
+		// Read more
+		return 0, nil, nil
+	})
+	for scanner.Scan() {
+		message := scanner.Bytes()
+		if crlf {
+			message = bytes.TrimSuffix(message, []byte("\r"))
+		}
+		if len(message) != 0 {
+			feed(string(message))
+		}
+	}
+	return scanner.Err()
+}
+
+// BuildMessages reads the given Reader as messages separated by delimiter, see ReadMessages, and feeds
+// every message to the Chain as its own message, split into words by split. A nil split splits words
+// on whitespace. Returns the amount of messages fed.
+//
+// Unlike Build, the last word of a message is never linked to the first word of the next one.
+func (c *Chain) BuildMessages(r io.Reader, delimiter string, split func(string) []string) (int, error) {
+	if split == nil {
+		split = strings.Fields
+	}
+	fed := 0
+	err := ReadMessages(r, delimiter, func(message string) {
+		if words := split(message); len(words) != 0 {
+			c.Feed(words)
+			fed++
+		}
+	})
+	return fed, err
+}
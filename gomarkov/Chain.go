@@ -45,8 +45,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Link is a Markov chain prefix of one or more words.
@@ -64,82 +68,544 @@ func (p Link) Shift(word string) {
 }
 
 // Chain contains a map ("chain") of links to a list of suffixes/pairs.
-// A Link is a string of prefixLen words joined with spaces.
+// A Link is stored as a tuple of tokens, the IDs of its words in the Chain dictionary.
 // A Pair is a single word and number of appearance after Link. A Link can have multiple Pairs.
+//
+// Alongside it, the Chain keeps a reverse map of the same transitions, where a Link is
+// the following words in reverse order and the Pairs are the words that came before it.
+// An empty word in the reverse map means the start of a message.
+//
+// A Chain is safe for concurrent use. Generating only takes a read lock, so any amount of
+// generation can run at the same time, waiting only for feeding and other changes to finish.
 type Chain struct {
-	chain       map[string]map[string]int
+	// lock guards everything below, it's shared with the views made by WithSampling
+	lock        *sync.RWMutex
+	chain       map[linkKey]*suffixes
+	reverse     map[linkKey]*suffixes
+	dict        *dictionary
 	linksLength int
+	endTokens   bool
+	// lower holds the tables for the shorter Links used for backing off, lower[i] has Links
+	// of the last i+1 words. It's nil when backoff is disabled.
+	lower    []map[linkKey]*suffixes
+	rng      *rand.Rand
+	sampling Sampling
+	// separator is put between the generated words
+	separator string
 }
 
-// NewChain returns a new Chain with prefixes of prefixLen words.
+// NewChain returns a new Chain with prefixes of prefixLen words,
+// with its own source of randomness seeded from the current time.
 func NewChain(linksLength int) *Chain {
-	return &Chain{make(map[string]map[string]int), linksLength}
+	return NewChainWithSource(linksLength, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewChainWithSource returns a new Chain with prefixes of prefixLen words,
+// which uses the given Source for all random choices.
+// The length is kept between 1 and MaxLinksLength.
+func NewChainWithSource(linksLength int, src rand.Source) *Chain {
+	if linksLength > MaxLinksLength {
+		linksLength = MaxLinksLength
+	}
+	if linksLength < 1 {
+		linksLength = 1
+	}
+	return &Chain{
+		lock:        &sync.RWMutex{},
+		chain:       make(map[linkKey]*suffixes),
+		reverse:     make(map[linkKey]*suffixes),
+		dict:        newDictionary(),
+		linksLength: linksLength,
+		rng:         rand.New(newLockedSource(src)),
+		separator:   " ",
+	}
+}
+
+// NewCharChain returns a new Chain for generating words instead of sentences, which is fed
+// the characters of every word (see Runes) and generates words without spaces between the characters.
+// It records end tokens, so that generated words end where fed words did.
+func NewCharChain(linksLength int) *Chain {
+	c := NewChain(linksLength)
+	c.SetEndTokens(true)
+	c.SetSeparator("")
+	return c
+}
+
+// Runes returns every character of the given string, for feeding a character Chain
+func Runes(s string) []string {
+	runes := make([]string, 0, len(s))
+	for _, r := range s {
+		runes = append(runes, string(r))
+	}
+	return runes
+}
+
+// SetSeparator sets the string put between generated words, a space by default
+func (c *Chain) SetSeparator(separator string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.separator = separator
+}
+
+// Separator returns the string put between generated words
+func (c *Chain) Separator() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.separator
+}
+
+// SetEndTokens sets whether Feed records an `END` token after the last word of every message,
+// letting Generate stop where messages naturally end. This should be set before anything is fed,
+// a Chain fed with and without end tokens would stop in some places, but not all.
+func (c *Chain) SetEndTokens(enabled bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.endTokens = enabled
+}
+
+// EndTokens reports whether the Chain records end tokens
+func (c *Chain) EndTokens() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.endTokens
+}
+
+// SetBackoff sets whether the Chain keeps tables for every Link length shorter than its own,
+// and backs off to the longest shorter Link that has suffixes when generation reaches a Link
+// without any. This lets longer Links be used on small corpora without cutting messages short.
+// The shorter tables are built from the Chain, so this can be changed at any time.
+func (c *Chain) SetBackoff(enabled bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.setBackoff(enabled)
+}
+
+// setBackoff enables or disables backoff, the caller should hold the write lock
+func (c *Chain) setBackoff(enabled bool) {
+	if !enabled {
+		c.lower = nil
+		return
+	}
+	if c.lower != nil {
+		return
+	}
+	c.lower = make([]map[linkKey]*suffixes, c.linksLength-1)
+	for i := range c.lower {
+		c.lower[i] = make(map[linkKey]*suffixes)
+	}
+	for key, choices := range c.chain {
+		for i, t := range choices.tokens {
+			c.addLower(key, t, choices.counts[i])
+		}
+	}
+}
+
+// Backoff reports whether the Chain backs off to shorter Links
+func (c *Chain) Backoff() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lower != nil
+}
+
+// SetSource replaces the source of randomness of the Chain
+func (c *Chain) SetSource(src rand.Source) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rng = rand.New(newLockedSource(src))
+}
+
+// SetSampling sets how the Chain picks the next word while generating
+func (c *Chain) SetSampling(sampling Sampling) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sampling = sampling
+}
+
+// Sampling returns how the Chain picks the next word while generating
+func (c *Chain) Sampling() Sampling {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sampling
+}
+
+// WithSampling returns a view of the Chain that generates using the given sampling, for picking
+// the sampling per call. The view shares its Links and source of randomness with the Chain,
+// but changing its settings doesn't change the Chain's, so it should not be kept around.
+func (c *Chain) WithSampling(sampling Sampling) *Chain {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	view := *c
+	view.sampling = sampling
+	return &view
+}
+
+// LinksLength returns the amount of words per Link of the Chain
+func (c *Chain) LinksLength() int {
+	return c.linksLength
 }
 
 // Build reads text from the provided Reader and
 // parses it into prefixes and suffixes that are stored in Chain.
+// All of the text is treated as one long message, use BuildMessages for text with many messages.
 func (c *Chain) Build(r io.Reader) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
 	br := bufio.NewReader(r)
-	l := make(Link, c.linksLength)
+	var k linkKey
 	for {
 		var s string
 		if _, err := fmt.Fscan(br, &s); err != nil {
 			break
 		}
-		key := l.String()
+		t := c.dict.intern(s)
+		c.add(k, t, 1)
+		k.shift(t, c.linksLength)
+	}
+}
 
-		// Add key if not exist with empty map
-		if _, ok := c.chain[key]; !ok {
-			c.chain[key] = make(map[string]int)
-		}
-		c.chain[key][s] = c.chain[key][s] + 1
-		l.Shift(s)
+// Feed feeds the given words into the Chain, returning the amount of Links it added
+func (c *Chain) Feed(words []string) int {
+	return c.FeedWeighted(words, 1)
+}
+
+// FeedWeighted feeds the given words into the Chain, with every transition counting as weight appearances.
+// Returns the amount of Links it added.
+func (c *Chain) FeedWeighted(words []string, weight float64) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	links := len(c.chain)
+	var k linkKey
+	for _, s := range words {
+		t := c.dict.intern(s)
+		c.add(k, t, weight)
+		k.shift(t, c.linksLength)
 	}
+	if c.endTokens && len(words) != 0 {
+		c.add(k, endToken, weight)
+	}
+	return len(c.chain) - links
+}
+
+// Unfeed removes the given words from the Chain, undoing a Feed of the same words.
+// Links that are left without any suffixes are removed.
+func (c *Chain) Unfeed(words []string) {
+	c.UnfeedWeighted(words, 1)
 }
 
-// Feed feeds the given words into the Chain
-func (c *Chain) Feed(words []string) {
-	l := make(Link, c.linksLength)
+// UnfeedWeighted removes the given words from the Chain, undoing a FeedWeighted of the same words and weight
+func (c *Chain) UnfeedWeighted(words []string, weight float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var k linkKey
 	for _, s := range words {
-		key := l.String()
-		// Add key if not exist with empty map
-		if _, ok := c.chain[key]; !ok {
-			c.chain[key] = make(map[string]int)
+		t := c.dict.lookup(s)
+		c.add(k, t, -weight)
+		k.shift(t, c.linksLength)
+	}
+	if c.endTokens && len(words) != 0 {
+		c.add(k, endToken, -weight)
+	}
+}
+
+// Scale multiplies the weight of every transition by the given factor, which has to be positive.
+// Generation is not changed by it, only the weights that later feeds are weighed against.
+// Weights too small for a float64 are kept at the smallest one, so that scaling never removes a transition.
+func (c *Chain) Scale(factor float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tables := append([]map[linkKey]*suffixes{c.chain, c.reverse}, c.lower...)
+	for _, table := range tables {
+		for _, choices := range table {
+			choices.total = 0
+			for i := range choices.counts {
+				choices.counts[i] = math.Max(choices.counts[i]*factor, math.SmallestNonzeroFloat64)
+				choices.total += choices.counts[i]
+			}
+			choices.resetAlias()
+		}
+	}
+}
+
+// Reset removes everything fed to the Chain, keeping its settings
+func (c *Chain) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.chain = make(map[linkKey]*suffixes)
+	c.reverse = make(map[linkKey]*suffixes)
+	c.dict = newDictionary()
+	if c.lower != nil {
+		c.lower = nil
+		c.setBackoff(true)
+	}
+}
+
+// add adds count appearances of the suffix t after the Link k, in both directions.
+// A negative count removes appearances instead.
+func (c *Chain) add(k linkKey, t token, count float64) {
+	addPair(c.chain, k, t, count)
+	addPair(c.reverse, c.reverseKey(k, t), k[0], count)
+	if c.lower != nil {
+		c.addLower(k, t, count)
+	}
+}
+
+// addLower adds count appearances of the suffix t to the shorter Links of the Link k
+func (c *Chain) addLower(k linkKey, t token, count float64) {
+	for i, table := range c.lower {
+		addPair(table, c.lowerKey(k, i+1), t, count)
+	}
+}
+
+// lowerKey returns the last n words of the Link k, as a key of the shorter table for n words
+func (c *Chain) lowerKey(k linkKey, n int) linkKey {
+	var lower linkKey
+	copy(lower[:n], k[c.linksLength-n:c.linksLength])
+	return lower
+}
+
+// suffixesOf returns the suffixes of the Link k. With backoff enabled, if the Link has no suffixes,
+// the suffixes of the longest shorter Link that has any are returned instead.
+func (c *Chain) suffixesOf(k linkKey) *suffixes {
+	if choices := c.chain[k]; choices != nil {
+		return choices
+	}
+	for n := len(c.lower); n > 0; n-- {
+		if choices := c.lower[n-1][c.lowerKey(k, n)]; choices != nil {
+			return choices
+		}
+	}
+	return nil
+}
+
+// addPair adds count appearances of the suffix t to the given key, creating the key if needed.
+// If the count drops to zero, the suffix is removed, as is the key when it has no suffixes left.
+func addPair(m map[linkKey]*suffixes, key linkKey, t token, count float64) {
+	// Add key if not exist with empty suffixes
+	choices, ok := m[key]
+	if !ok {
+		if count <= 0 {
+			return
+		}
+		choices = &suffixes{}
+		m[key] = choices
+	}
+	choices.add(t, count)
+	if len(choices.tokens) == 0 {
+		delete(m, key)
+	}
+}
+
+// reverseKey returns the reverse map key for the suffix t following the Link k,
+// which is t and the words of k, except the first one, in reverse order
+func (c *Chain) reverseKey(k linkKey, t token) linkKey {
+	var r linkKey
+	r[0] = t
+	for i := 1; i < c.linksLength; i++ {
+		r[i] = k[c.linksLength-i]
+	}
+	return r
+}
+
+// sortedKeys returns the keys of the given map in a fixed order
+func sortedKeys(m map[linkKey]*suffixes) []linkKey {
+	keys := make([]linkKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	return keys
+}
+
+// buildReverse rebuilds the reverse map from the chain
+func (c *Chain) buildReverse() {
+	c.reverse = make(map[linkKey]*suffixes)
+	// Go through the links in a fixed order, so that the reverse suffixes are always in the same order
+	for _, key := range sortedKeys(c.chain) {
+		choices := c.chain[key]
+		for i, t := range choices.tokens {
+			addPair(c.reverse, c.reverseKey(key, t), key[0], choices.counts[i])
 		}
-		c.chain[key][s] = c.chain[key][s] + 1
-		l.Shift(s)
 	}
 }
 
 // Generate returns a string of at most n words generated from Chain.
 func (c *Chain) Generate(n int) string {
-	l := make(Link, c.linksLength)
-	var words []string
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.join(c.generate(linkKey{}, n, c.rng))
+}
+
+// GenerateSeeded returns a string of at most n words generated from Chain, along with the seed
+// it was generated with. Calling GenerateWithSeed with that seed on the same Chain
+// generates the same string again.
+func (c *Chain) GenerateSeeded(n int) (string, int64) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	seed := c.rng.Int63()
+	return c.generateWithSeed(n, seed), seed
+}
+
+// GenerateWithSeed returns a string of at most n words generated from Chain using the given seed
+func (c *Chain) GenerateWithSeed(n int, seed int64) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.generateWithSeed(n, seed)
+}
+
+// generateWithSeed is GenerateWithSeed, the caller should hold the read lock
+func (c *Chain) generateWithSeed(n int, seed int64) string {
+	rng := rand.New(rand.NewSource(seed))
+	return c.join(c.generate(linkKey{}, n, rng))
+}
+
+// GenerateFrom returns a string of at most n words generated from Chain, starting with the given seed words.
+// If the seed was never seen at the start of a message, generation continues from a random
+// Link that ends with the last seed word instead. Returns an empty string if that word is not in the Chain.
+func (c *Chain) GenerateFrom(seed []string, n int) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if len(seed) == 0 {
+		return c.join(c.generate(linkKey{}, n, c.rng))
+	}
+	// Build the Link as if the seed was the start of a message
+	var k linkKey
+	for _, s := range seed {
+		k.shift(c.dict.lookup(s), c.linksLength)
+	}
+	words := seed
+	if c.suffixesOf(k) == nil {
+		// Look for the last word of the seed anywhere in the chain instead
+		var ok bool
+		if k, ok = c.findLink(c.dict.lookup(seed[len(seed)-1]), c.rng); !ok {
+			return ""
+		}
+		words = c.dict.text(c.linkTokens(k))
+	}
+	if len(words) >= n {
+		return strings.Join(words[:n], c.separator)
+	}
+	words = append(words[:len(words):len(words)], c.dict.text(c.generate(k, n-len(words), c.rng))...)
+	return strings.Join(words, c.separator)
+}
+
+// GenerateAround returns a string of at most n words generated from Chain that contains the given word.
+// The words before it are generated backwards until the start of a message, and the words after it
+// are generated forwards. Returns an empty string if the word is not in the Chain.
+func (c *Chain) GenerateAround(word string, n int) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	r, ok := findKey(c.reverse, 0, c.dict.lookup(word), c.rng)
+	if !ok {
+		return ""
+	}
+	// Searching the reverse map also finds the word at the end of a message, flip the key to read forwards
+	k := c.flip(r)
+	middle := c.linkTokens(k)
+	if len(middle) >= n {
+		return c.join(middle[len(middle)-n:])
+	}
+	// Give half of what's left to the words before, the rest to the words after
+	before := c.generateBackward(k, (n-len(middle))/2, c.rng)
+	tokens := append(before, middle...)
+	tokens = append(tokens, c.generate(k, n-len(tokens), c.rng)...)
+	return c.join(tokens)
+}
+
+// join returns the given tokens as a string of words
+func (c *Chain) join(tokens []token) string {
+	return strings.Join(c.dict.text(tokens), c.separator)
+}
+
+// linkTokens returns the tokens of the given Link, without the empty words at the start of a message
+func (c *Chain) linkTokens(k linkKey) []token {
+	var tokens []token
+	for _, t := range k[:c.linksLength] {
+		if t != 0 {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// generateBackward walks the reverse map from the given Link, returning at most n tokens
+// that come before it, in their original order
+func (c *Chain) generateBackward(k linkKey, n int, rng *rand.Rand) []token {
+	if k[0] == 0 {
+		// The Link is already at the start of a message
+		return nil
+	}
+	r := c.flip(k)
+	var tokens []token
 	for i := 0; i < n; i++ {
-		choices := c.chain[l.String()]
-		if len(choices) == 0 {
+		choices := c.reverse[r]
+		if choices == nil {
 			break
 		}
-
-		choicesLen := 0
-		for _, v := range choices {
-			choicesLen += v
+		prev := choices.pickWith(rng, c.sampling)
+		if prev == 0 {
+			// Reached the start of a message
+			break
 		}
+		tokens = append(tokens, prev)
+		r.shift(prev, c.linksLength)
+	}
+	// Flip the tokens back into reading order
+	for i, j := 0, len(tokens)-1; i < j; i, j = i+1, j-1 {
+		tokens[i], tokens[j] = tokens[j], tokens[i]
+	}
+	return tokens
+}
 
-		index := rand.Intn(choicesLen)
+// flip returns the given key with its words in reverse order
+func (c *Chain) flip(k linkKey) linkKey {
+	var r linkKey
+	for i := 0; i < c.linksLength; i++ {
+		r[i] = k[c.linksLength-1-i]
+	}
+	return r
+}
 
-		next := ""
-		for k, v := range choices {
-			if (index - v) <= 0 {
-				next = k
-				break
-			}
-			index -= v
+// findLink returns a random Link that ends with the given token
+func (c *Chain) findLink(t token, rng *rand.Rand) (linkKey, bool) {
+	return findKey(c.chain, c.linksLength-1, t, rng)
+}
+
+// findKey returns a random key of the given map that has the given token at the given position
+func findKey(m map[linkKey]*suffixes, position int, t token, rng *rand.Rand) (linkKey, bool) {
+	if t == 0 || t == unknownToken {
+		return linkKey{}, false
+	}
+	var candidates []linkKey
+	for key := range m {
+		if key[position] == t {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 0 {
+		return linkKey{}, false
+	}
+	// Sort the candidates, so that the same random numbers always give the same Link
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].less(candidates[j]) })
+	return candidates[rng.Intn(len(candidates))], true
+}
+
+// generate walks the Chain from the given Link, returning at most n tokens.
+func (c *Chain) generate(k linkKey, n int, rng *rand.Rand) []token {
+	var tokens []token
+	for i := 0; i < n; i++ {
+		choices := c.suffixesOf(k)
+		if choices == nil {
+			break
 		}
 
-		words = append(words, next)
-		l.Shift(next)
+		next := choices.pickWith(rng, c.sampling)
+		if next == endToken {
+			// The message ends here
+			break
+		}
+		tokens = append(tokens, next)
+		k.shift(next, c.linksLength)
 	}
-	return strings.Join(words, " ")
+	return tokens
 }
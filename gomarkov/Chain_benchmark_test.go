@@ -1,6 +1,8 @@
 package gomarkov
 
 import (
+	"math/rand"
+	"runtime"
 	"strconv"
 	"testing"
 )
@@ -82,3 +84,121 @@ func Benchmark_Check_performance_of_array_shift_right_with_copy(t *testing.B) {
 
 	ShiftRight1(p)
 }
+
+// largeCorpus creates a corpus of messages with Zipf-distributed words, like real text,
+// so that the most common words are followed by thousands of different suffixes
+func largeCorpus(messages int) [][]string {
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.1, 1, 20000)
+	corpus := make([][]string, messages)
+	for i := range corpus {
+		message := make([]string, 5+rng.Intn(20))
+		for j := range message {
+			message[j] = "w" + strconv.FormatUint(zipf.Uint64(), 10)
+		}
+		corpus[i] = message
+	}
+	return corpus
+}
+
+func largeChain() *Chain {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	for _, message := range largeCorpus(50000) {
+		c.Feed(message)
+	}
+	return c
+}
+
+// mostPopular returns the suffixes of the Link with the most suffixes in the Chain
+func mostPopular(c *Chain) *suffixes {
+	var popular *suffixes
+	for _, choices := range c.chain {
+		if popular == nil || len(choices.tokens) > len(popular.tokens) {
+			popular = choices
+		}
+	}
+	return popular
+}
+
+// pickLinear is the previous sampling algorithm, which sums up the counts
+// and scans the suffix map for every pick
+func pickLinear(choices map[string]int, rng *rand.Rand) string {
+	choicesLen := 0
+	for _, v := range choices {
+		choicesLen += v
+	}
+
+	index := rng.Intn(choicesLen)
+
+	next := ""
+	for k, v := range choices {
+		if (index - v) < 0 {
+			next = k
+			break
+		}
+		index -= v
+	}
+	return next
+}
+
+func Benchmark_Check_performance_of_Generate_on_large_corpus(t *testing.B) {
+	c := largeChain()
+	t.ResetTimer()
+
+	for i := 0; i < t.N; i++ {
+		c.Generate(30)
+	}
+}
+
+func Benchmark_Check_performance_of_sampling_with_linear_map_scan(t *testing.B) {
+	c := largeChain()
+	popular := mostPopular(c)
+	choices := make(map[string]int, len(popular.tokens))
+	for i, t := range popular.tokens {
+		choices[c.dict.words[t]] = int(popular.counts[i])
+	}
+	rng := rand.New(rand.NewSource(1))
+	t.ResetTimer()
+
+	for i := 0; i < t.N; i++ {
+		pickLinear(choices, rng)
+	}
+}
+
+func Benchmark_Check_performance_of_sampling_with_alias_table(t *testing.B) {
+	popular := mostPopular(largeChain())
+	rng := rand.New(rand.NewSource(1))
+	t.ResetTimer()
+
+	for i := 0; i < t.N; i++ {
+		popular.pick(rng)
+	}
+}
+
+// heapInUse returns the bytes currently allocated on the heap, after a garbage collection
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func Benchmark_Check_memory_usage_of_large_corpus(t *testing.B) {
+	corpus := largeCorpus(50000)
+	for linksLength := 1; linksLength <= 3; linksLength++ {
+		t.Run("links="+strconv.Itoa(linksLength), func(t *testing.B) {
+			t.ReportAllocs()
+			var used uint64
+			for i := 0; i < t.N; i++ {
+				before := heapInUse()
+				c := NewChainWithSource(linksLength, rand.NewSource(1))
+				for _, message := range corpus {
+					c.Feed(message)
+				}
+				used += heapInUse() - before
+				runtime.KeepAlive(c)
+			}
+			t.ReportMetric(float64(used)/float64(t.N), "heap-bytes/op")
+		})
+	}
+}
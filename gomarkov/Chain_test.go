@@ -0,0 +1,433 @@
+package gomarkov
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var testCorpus = []string{
+	"One fish two fish red fish blue fish",
+	"this one has a little star",
+	"this one has a little car",
+	"say what a lot of fish there are",
+	"some are red and some are blue",
+	"some are old and some are new",
+}
+
+func newTestChain(linksLength int, seed int64) *Chain {
+	c := NewChainWithSource(linksLength, rand.NewSource(seed))
+	for _, line := range testCorpus {
+		c.Feed(strings.Fields(line))
+	}
+	return c
+}
+
+func Test_Same_source_generates_the_same_text(t *testing.T) {
+	for linksLength := 1; linksLength <= 3; linksLength++ {
+		a := newTestChain(linksLength, 42)
+		b := newTestChain(linksLength, 42)
+		for i := 0; i < 20; i++ {
+			if textA, textB := a.Generate(30), b.Generate(30); textA != textB {
+				t.Fatalf("links %d: generated %q and %q from the same source", linksLength, textA, textB)
+			}
+		}
+	}
+}
+
+func Test_GenerateWithSeed_regenerates_GenerateSeeded(t *testing.T) {
+	c := newTestChain(2, 7)
+	for i := 0; i < 20; i++ {
+		text, seed := c.GenerateSeeded(30)
+		if again := c.GenerateWithSeed(30, seed); again != text {
+			t.Fatalf("seed %d generated %q, then %q", seed, text, again)
+		}
+	}
+}
+
+func Test_Suffixes_are_picked_in_proportion_to_their_counts(t *testing.T) {
+	const rare, common, medium, gone = 1, 2, 3, 4
+	choices := &suffixes{}
+	choices.add(rare, 1)
+	choices.add(common, 6)
+	choices.add(medium, 3)
+	choices.add(gone, 2)
+	choices.add(gone, -2)
+
+	rng := rand.New(rand.NewSource(1))
+	picked := map[token]int{}
+	const picks = 100000
+	for i := 0; i < picks; i++ {
+		picked[choices.pick(rng)]++
+	}
+	for i, suffix := range choices.tokens {
+		expected := float64(choices.counts[i]) / float64(choices.total)
+		if actual := float64(picked[suffix]) / picks; actual < expected-0.01 || actual > expected+0.01 {
+			t.Errorf("token %d picked %.3f of the time, expected %.3f", suffix, actual, expected)
+		}
+	}
+	if picked[gone] != 0 {
+		t.Errorf("removed suffix was picked %d times", picked[gone])
+	}
+}
+
+func Test_Generate_stops_at_end_tokens(t *testing.T) {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	c.SetEndTokens(true)
+	c.Feed([]string{"go", "tusk", "go"})
+	c.Feed([]string{"tusk", "go"})
+	for i := 0; i < 20; i++ {
+		text := c.Generate(30)
+		if words := strings.Fields(text); words[len(words)-1] != "go" {
+			t.Fatalf("generated %q, which does not end where a message ended", text)
+		}
+	}
+}
+
+func Test_Load_reads_what_Save_wrote(t *testing.T) {
+	c := newTestChain(2, 1)
+	c.SetEndTokens(true)
+	c.Feed(strings.Fields("a message with an end"))
+	var snapshot bytes.Buffer
+	if err := c.Save(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.LinksLength() != 2 || !loaded.EndTokens() {
+		t.Fatalf("loaded links %d, end tokens %v", loaded.LinksLength(), loaded.EndTokens())
+	}
+	if len(loaded.chain) != len(c.chain) || len(loaded.reverse) != len(c.reverse) {
+		t.Fatalf("loaded %d links and %d reverse links, expected %d and %d",
+			len(loaded.chain), len(loaded.reverse), len(c.chain), len(c.reverse))
+	}
+	for key, choices := range c.chain {
+		loadedChoices := loaded.chain[key]
+		if loadedChoices == nil || loadedChoices.total != choices.total {
+			t.Fatalf("link %v was not loaded the same", c.dict.link(key, 2))
+		}
+	}
+}
+
+func Test_Load_rejects_corrupt_snapshots(t *testing.T) {
+	var snapshot bytes.Buffer
+	if err := newTestChain(2, 1).Save(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	// Every truncated snapshot fails instead of loading part of the chain
+	for i := 0; i < snapshot.Len(); i++ {
+		if _, err := Load(bytes.NewReader(snapshot.Bytes()[:i])); err == nil {
+			t.Fatalf("loaded a snapshot cut at %d of %d bytes", i, snapshot.Len())
+		}
+	}
+	header := func(values ...uint64) *bytes.Buffer {
+		b := bytes.NewBufferString(snapshotMagic)
+		sw := snapshotWriter{w: bufio.NewWriter(b)}
+		for _, v := range values {
+			sw.uvarint(v)
+		}
+		sw.w.Flush()
+		return b
+	}
+	for name, corrupt := range map[string]*bytes.Buffer{
+		"links length":    header(SnapshotVersion, MaxLinksLength+1, 0, 0, 0),
+		"string length":   header(SnapshotVersion, 1, snapshotSeparator, 1<<62),
+		"v1 links length": header(1, 0, 0),
+	} {
+		if _, err := Load(corrupt); err != ErrBadSnapshot {
+			t.Errorf("loading a snapshot with a corrupt %s returned %v", name, err)
+		}
+	}
+}
+
+func Test_Backoff_continues_past_unknown_links(t *testing.T) {
+	c := NewChainWithSource(2, rand.NewSource(1))
+	c.Feed(strings.Fields("the cat sat"))
+	c.Feed(strings.Fields("a dog sat down"))
+	// Without backoff, the unknown seed is dropped and the message ends early
+	if text := c.GenerateFrom([]string{"big", "cat"}, 10); text != "the cat sat" {
+		t.Fatalf("generated %q without backoff, expected %q", text, "the cat sat")
+	}
+	c.SetBackoff(true)
+	if text := c.GenerateFrom([]string{"big", "cat"}, 10); text != "big cat sat down" {
+		t.Fatalf("generated %q with backoff, expected %q", text, "big cat sat down")
+	}
+	// Backing off works the same for Links fed after enabling it
+	c.Feed(strings.Fields("down the hill"))
+	if text := c.GenerateFrom([]string{"big", "cat"}, 10); text != "big cat sat down the hill" {
+		t.Fatalf("generated %q with backoff, expected %q", text, "big cat sat down the hill")
+	}
+}
+
+func Test_Score_prefers_sentences_like_the_corpus(t *testing.T) {
+	c := newTestChain(1, 1)
+	c.SetEndTokens(true)
+	seen := c.Score(strings.Fields("this one has a little car"))
+	unseen := c.Score(strings.Fields("car little a has one this"))
+	if seen.Unknown != 1 {
+		// Only the end token after "car" was never fed
+		t.Errorf("seen sentence has %d unknown transitions, expected 1", seen.Unknown)
+	}
+	if seen.LogProb <= unseen.LogProb || seen.Perplexity >= unseen.Perplexity {
+		t.Errorf("seen sentence scored %+v, unseen sentence scored %+v", seen, unseen)
+	}
+	if seen.Tokens != 7 {
+		t.Errorf("scored %d tokens, expected 7", seen.Tokens)
+	}
+}
+
+func Test_Score_of_an_empty_chain_is_impossible(t *testing.T) {
+	for _, endTokens := range []bool{false, true} {
+		c := NewChain(1)
+		c.SetEndTokens(endTokens)
+		score := c.Score(strings.Fields("anything at all"))
+		if !math.IsInf(score.LogProb, -1) || !math.IsInf(score.Perplexity, 1) || score.Unknown != score.Tokens {
+			t.Errorf("scored %+v with end tokens %v, expected an impossible sentence", score, endTokens)
+		}
+		// And so is it once everything was unfed
+		c.Feed(strings.Fields("anything at all"))
+		c.Unfeed(strings.Fields("anything at all"))
+		if score := c.Score(strings.Fields("anything at all")); !math.IsInf(score.Perplexity, 1) {
+			t.Errorf("scored %+v after unfeeding everything with end tokens %v", score, endTokens)
+		}
+	}
+}
+
+func Test_Sampling_narrows_the_picked_suffixes(t *testing.T) {
+	const rare, common, medium = 1, 2, 3
+	choices := &suffixes{}
+	choices.add(rare, 1)
+	choices.add(common, 6)
+	choices.add(medium, 3)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if picked := choices.pickWith(rng, Sampling{TopK: 1}); picked != common {
+			t.Fatalf("top 1 picked token %d", picked)
+		}
+		if picked := choices.pickWith(rng, Sampling{TopP: 0.9}); picked == rare {
+			t.Fatalf("top 0.9 picked the rarest token")
+		}
+	}
+	// A low temperature picks the most common suffix more often than its count alone would
+	picked := 0
+	const picks = 10000
+	for i := 0; i < picks; i++ {
+		if choices.pickWith(rng, Sampling{Temperature: 0.5}) == common {
+			picked++
+		}
+	}
+	if share := float64(picked) / picks; share < 0.75 {
+		t.Errorf("temperature 0.5 picked the most common token %.3f of the time, expected about 0.78", share)
+	}
+}
+
+func Test_Stats_describe_the_chain(t *testing.T) {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	c.Feed([]string{"a", "b"})
+	c.Feed([]string{"a", "c"})
+	stats := c.Stats(1)
+	if stats.Links != 2 || stats.Transitions != 4 || stats.Vocabulary != 3 {
+		t.Errorf("counted %d links, %d transitions and %d words, expected 2, 4 and 3",
+			stats.Links, stats.Transitions, stats.Vocabulary)
+	}
+	// Half the transitions are from "a", which has two equally likely suffixes
+	if stats.Branching != 1.5 || stats.Entropy != 0.5 {
+		t.Errorf("branching %.3f and entropy %.3f, expected 1.5 and 0.5", stats.Branching, stats.Entropy)
+	}
+	if len(stats.TopWords) != 1 || stats.TopWords[0] != (WordCount{Word: "a", Count: 2}) {
+		t.Errorf("top words %v, expected only a", stats.TopWords)
+	}
+}
+
+func Test_Merge_adds_the_transitions_Diff_reports(t *testing.T) {
+	live := NewChainWithSource(1, rand.NewSource(1))
+	live.Feed(strings.Fields("the cat sat"))
+	curated := NewChainWithSource(1, rand.NewSource(1))
+	curated.Feed(strings.Fields("the dog sat"))
+
+	diff, err := live.Diff(curated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "the dog" and "dog sat" are new, "the cat" and "cat sat" are gone
+	if len(diff.Added) != 2 || len(diff.Removed) != 2 {
+		t.Fatalf("diff added %v and removed %v, expected 2 of each", diff.Added, diff.Removed)
+	}
+
+	before := live.Clone()
+	if err := live.Merge(curated, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if diff, _ := before.Diff(live); len(diff.Added) != 2 || len(diff.Removed) != 0 {
+		t.Fatalf("merge added %v and removed %v, expected 2 and none", diff.Added, diff.Removed)
+	}
+	if choices := live.chain[linkKey{live.dict.lookup("the")}]; choices.total != 1.5 {
+		t.Errorf("\"the\" has a total weight of %v after the merge, expected 1.5", choices.total)
+	}
+	// Merging with the opposite weight takes it back out
+	if err := live.Merge(curated, -0.5); err != nil {
+		t.Fatal(err)
+	}
+	if diff, _ := before.Diff(live); len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Fatalf("unmerging left %v added and %v removed", diff.Added, diff.Removed)
+	}
+	if err := live.Merge(NewChain(2), 1); err != ErrLinksLength {
+		t.Errorf("merging chains of different lengths returned %v", err)
+	}
+}
+
+func Test_Export_writes_the_neighbourhood_of_a_word(t *testing.T) {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	c.SetEndTokens(true)
+	c.Feed(strings.Fields("a b c d e"))
+
+	var exported bytes.Buffer
+	if err := c.Export(&exported, FormatJSON, "c", 1); err != nil {
+		t.Fatal(err)
+	}
+	var links []exportedLink
+	if err := json.Unmarshal(exported.Bytes(), &links); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s", err, exported.String())
+	}
+	// "c" itself, with "b" before it and "d" after it
+	if len(links) != 3 || links[0].Link[0] != "b" || links[1].Link[0] != "c" || links[2].Link[0] != "d" {
+		t.Fatalf("exported %s, expected the links b, c and d", exported.String())
+	}
+
+	exported.Reset()
+	if err := c.Export(&exported, FormatDOT, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if dot := exported.String(); !strings.HasPrefix(dot, "digraph") || strings.Count(dot, "->") != 6 {
+		t.Fatalf("exported %s, expected a digraph with 6 edges", dot)
+	}
+	if err := c.Export(&exported, FormatJSON, "z", 1); err != ErrUnknownWord {
+		t.Errorf("exporting an unknown word returned %v", err)
+	}
+}
+
+func Test_Prune_and_Cap_remove_rare_transitions(t *testing.T) {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	c.SetBackoff(true)
+	c.Feed(strings.Fields("the cat sat"))
+	c.Feed(strings.Fields("the cat ran"))
+	c.Feed(strings.Fields("a dog sat"))
+
+	// Only "the cat" was fed twice
+	if pruned := c.Prune(2); pruned != 5 {
+		t.Fatalf("pruned %d transitions, expected 5", pruned)
+	}
+	if len(c.chain) != 2 || len(c.dict.words) != reservedTokens+2 {
+		t.Fatalf("%d links and %d words are left, expected 2 links with 2 words", len(c.chain), len(c.dict.words))
+	}
+	if text := c.Generate(10); text != "the cat" {
+		t.Fatalf("generated %q after pruning, expected %q", text, "the cat")
+	}
+
+	// "cat" is now the least used link
+	c.Feed(strings.Fields("cat nap"))
+	if evicted := c.Cap(2); evicted != 1 || c.chain[linkKey{c.dict.lookup("cat")}] != nil {
+		t.Fatalf("evicted %d links, expected only \"cat\"", evicted)
+	}
+	if c.dict.lookup("nap") != unknownToken {
+		t.Errorf("\"nap\" is still in the dictionary after its link was evicted")
+	}
+}
+
+func Test_Char_chain_makes_up_words(t *testing.T) {
+	c := NewCharChain(2)
+	c.SetSource(rand.NewSource(1))
+	for _, word := range strings.Fields("banana bandana cabana") {
+		c.Feed(Runes(word))
+	}
+	for i := 0; i < 20; i++ {
+		word := c.Generate(200)
+		if word == "" || strings.Contains(word, " ") || !strings.HasSuffix(word, "ana") {
+			t.Fatalf("generated %q, expected a word ending like the fed words", word)
+		}
+	}
+	var snapshot bytes.Buffer
+	if err := c.Save(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Separator() != "" {
+		t.Errorf("loaded separator %q, expected none", loaded.Separator())
+	}
+}
+
+func Test_BuildMessages_keeps_messages_apart(t *testing.T) {
+	c := NewChainWithSource(1, rand.NewSource(1))
+	fed, err := c.BuildMessages(strings.NewReader("the cat sat\r\n\nthe dog ran\n"), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fed != 2 {
+		t.Fatalf("fed %d messages, expected 2", fed)
+	}
+	// "sat" ended a message, so nothing follows it
+	if choices := c.chain[linkKey{c.dict.lookup("sat")}]; choices != nil {
+		t.Errorf("the end of a message is linked to %v", c.dict.text(choices.tokens))
+	}
+
+	fed, err = c.BuildMessages(strings.NewReader("one;two;;three"), ";", nil)
+	if err != nil || fed != 3 {
+		t.Errorf("fed %d messages separated by ;, expected 3 (error %v)", fed, err)
+	}
+}
+
+func Test_Chain_can_be_fed_while_generating(t *testing.T) {
+	c := newTestChain(2, 1)
+	c.SetBackoff(true)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				words := strings.Fields(testCorpus[j%len(testCorpus)])
+				c.Feed(words)
+				c.Unfeed(words)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Generate(20)
+				c.GenerateAround("fish", 20)
+				c.WithSampling(Sampling{TopK: 2}).GenerateFrom([]string{"some"}, 20)
+				c.Score([]string{"one", "fish"})
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			c.Stats(5)
+			c.Export(ioutil.Discard, FormatJSON, "fish", 1)
+			c.Clone().Feed([]string{"cloned"})
+		}
+	}()
+	wg.Wait()
+	// Everything fed concurrently was unfed, so the Chain is back to what it was
+	diff, err := c.Diff(newTestChain(2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("chain changed after feeding and unfeeding concurrently: %+v", diff)
+	}
+}
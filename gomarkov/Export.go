@@ -0,0 +1,196 @@
+package gomarkov
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExportFormat is a format a Chain can be exported in
+type ExportFormat string
+
+const (
+	// FormatDOT is the Graphviz DOT format, with a node for every Link and an edge for every suffix
+	FormatDOT ExportFormat = "dot"
+	// FormatJSON is JSON, with every Link and the counts of its suffixes
+	FormatJSON ExportFormat = "json"
+)
+
+var (
+	// ErrExportFormat is returned when exporting in an unknown format
+	ErrExportFormat = errors.New("gomarkov: unknown export format")
+	// ErrUnknownWord is returned when exporting the neighbourhood of a word that is not in the Chain
+	ErrUnknownWord = errors.New("gomarkov: word is not in the chain")
+)
+
+// exportedLink is a Link in the JSON export
+type exportedLink struct {
+	Link     Link             `json:"link"`
+	Suffixes []exportedSuffix `json:"suffixes"`
+}
+
+// exportedSuffix is a suffix in the JSON export, the end of a message has End set and no word
+type exportedSuffix struct {
+	Word  string  `json:"word,omitempty"`
+	End   bool    `json:"end,omitempty"`
+	Count float64 `json:"count"`
+}
+
+// Export writes the Chain to the given Writer in the given format. With an empty word, the whole
+// Chain is exported, otherwise only the Links containing the word are, along with every Link up to
+// depth transitions before or after them. Links are written in a fixed order.
+func (c *Chain) Export(w io.Writer, format ExportFormat, word string, depth int) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var keys []linkKey
+	if word == "" {
+		keys = sortedKeys(c.chain)
+	} else {
+		t := c.dict.lookup(word)
+		if t == unknownToken || t == 0 {
+			return ErrUnknownWord
+		}
+		keys = c.neighbourhood(t, depth)
+	}
+	bw := bufio.NewWriter(w)
+	var err error
+	switch format {
+	case FormatDOT:
+		err = c.exportDOT(bw, keys)
+	case FormatJSON:
+		err = c.exportJSON(bw, keys)
+	default:
+		return ErrExportFormat
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// neighbourhood returns the Links that contain the given token, and the Links up to depth
+// transitions away from them in either direction, sorted
+func (c *Chain) neighbourhood(t token, depth int) []linkKey {
+	found := make(map[linkKey]bool)
+	var current []linkKey
+	for key := range c.chain {
+		for _, word := range key[:c.linksLength] {
+			if word == t {
+				found[key] = true
+				current = append(current, key)
+				break
+			}
+		}
+	}
+	for step := 0; step < depth && len(current) > 0; step++ {
+		var next []linkKey
+		visit := func(key linkKey) {
+			if c.chain[key] != nil && !found[key] {
+				found[key] = true
+				next = append(next, key)
+			}
+		}
+		for _, key := range current {
+			// The Links after this one
+			for _, suffix := range c.chain[key].tokens {
+				if suffix != endToken {
+					after := key
+					after.shift(suffix, c.linksLength)
+					visit(after)
+				}
+			}
+			// And the ones before it, which are the Link without its last word, with a word in front
+			if last := key[c.linksLength-1]; last != 0 {
+				var before linkKey
+				copy(before[1:c.linksLength], key[:c.linksLength-1])
+				if choices := c.reverse[c.reverseKey(before, last)]; choices != nil {
+					for _, prev := range choices.tokens {
+						before[0] = prev
+						visit(before)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	keys := make([]linkKey, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	return keys
+}
+
+// exportDOT writes the given Links as a Graphviz digraph
+func (c *Chain) exportDOT(w *bufio.Writer, keys []linkKey) error {
+	ids := make(map[linkKey]int)
+	node := func(key linkKey) int {
+		id, ok := ids[key]
+		if !ok {
+			id = len(ids)
+			ids[key] = id
+			label := "START"
+			if words := c.linkTokens(key); len(words) != 0 {
+				label = strings.Join(c.dict.text(words), c.separator)
+			}
+			fmt.Fprintf(w, "\tn%d [label=%s];\n", id, dotQuote(label))
+		}
+		return id
+	}
+	fmt.Fprintln(w, "digraph chain {")
+	fmt.Fprintln(w, "\tend [label=\"END\", shape=box];")
+	for _, key := range keys {
+		from := node(key)
+		choices := c.chain[key]
+		for i, suffix := range choices.tokens {
+			if suffix == endToken {
+				fmt.Fprintf(w, "\tn%d -> end [label=\"%g\"];\n", from, choices.counts[i])
+				continue
+			}
+			after := key
+			after.shift(suffix, c.linksLength)
+			fmt.Fprintf(w, "\tn%d -> n%d [label=\"%g\"];\n", from, node(after), choices.counts[i])
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// dotQuote returns the given string as a DOT string
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// exportJSON writes the given Links as a JSON array, one Link at a time
+func (c *Chain) exportJSON(w *bufio.Writer, keys []linkKey) error {
+	w.WriteString("[")
+	for i, key := range keys {
+		choices := c.chain[key]
+		link := exportedLink{
+			Link:     c.dict.link(key, c.linksLength),
+			Suffixes: make([]exportedSuffix, len(choices.tokens)),
+		}
+		for j, suffix := range choices.tokens {
+			link.Suffixes[j] = exportedSuffix{
+				Word:  c.dict.words[suffix],
+				End:   suffix == endToken,
+				Count: choices.counts[j],
+			}
+		}
+		encoded, err := json.Marshal(link)
+		if err != nil {
+			return err
+		}
+		if i != 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n")
+		w.Write(encoded)
+	}
+	_, err := w.WriteString("\n]\n")
+	return err
+}
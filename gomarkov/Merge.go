@@ -0,0 +1,177 @@
+package gomarkov
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// ErrLinksLength is returned when combining two Chains with different Link lengths
+var ErrLinksLength = errors.New("gomarkov: chains have different links lengths")
+
+// Transition is a single suffix following a Link
+type Transition struct {
+	Link Link
+	// Suffix is the word following the Link, empty when End is set
+	Suffix string
+	// End is set when the Link is followed by the end of a message
+	End bool
+	// Weight is the amount of times the suffix followed the Link
+	Weight float64
+}
+
+// Diff is the difference between the transitions of two Chains
+type Diff struct {
+	// Added are the transitions of the other Chain that are not in this one
+	Added []Transition
+	// Removed are the transitions of this Chain that are not in the other one
+	Removed []Transition
+}
+
+// Merge adds every transition of the other Chain to this one, with its count multiplied by weight.
+// A weight of 1 is the same as feeding this Chain everything that was fed to the other one, and a
+// negative weight takes a previous merge back out. End tokens are merged as they are, so both Chains
+// should record them or not. Both Chains need to have the same Link length.
+// Two Chains should not be merged into each other at the same time, as they would wait for each other.
+func (c *Chain) Merge(other *Chain, weight float64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if other.lock != c.lock {
+		other.lock.RLock()
+		defer other.lock.RUnlock()
+	}
+	if other.linksLength != c.linksLength {
+		return ErrLinksLength
+	}
+	if weight == 0 {
+		return nil
+	}
+	// Translate the tokens of the other Chain to the tokens of this one
+	tokens := make([]token, len(other.dict.words))
+	for i, word := range other.dict.words {
+		if i < reservedTokens {
+			tokens[i] = token(i)
+		} else {
+			tokens[i] = c.dict.intern(word)
+		}
+	}
+	// Go through the links in a fixed order, so that merging is always done the same way
+	for _, key := range sortedKeys(other.chain) {
+		choices := other.chain[key]
+		if choices == nil {
+			// Only happens when merging a Chain into itself with a negative weight
+			continue
+		}
+		var k linkKey
+		for i, t := range key[:c.linksLength] {
+			k[i] = tokens[t]
+		}
+		// Copy the suffixes first, in case this is the same Chain
+		suffixTokens := append([]token(nil), choices.tokens...)
+		counts := append([]float64(nil), choices.counts...)
+		for i, t := range suffixTokens {
+			c.add(k, tokens[t], counts[i]*weight)
+		}
+	}
+	return nil
+}
+
+// Diff returns the transitions that are only in one of the two Chains, no matter their weights.
+// Both Chains need to have the same Link length.
+func (c *Chain) Diff(other *Chain) (Diff, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if other.lock != c.lock {
+		other.lock.RLock()
+		defer other.lock.RUnlock()
+	}
+	if other.linksLength != c.linksLength {
+		return Diff{}, ErrLinksLength
+	}
+	return Diff{
+		Added:   missingTransitions(other, c),
+		Removed: missingTransitions(c, other),
+	}, nil
+}
+
+// missingTransitions returns the transitions of the Chain a that are not in the Chain b
+func missingTransitions(a, b *Chain) []Transition {
+	var missing []Transition
+	for _, key := range sortedKeys(a.chain) {
+		choices := a.chain[key]
+		// Find the same Link in b, if all of its words are known there
+		var other *suffixes
+		var k linkKey
+		known := true
+		for i, t := range key[:a.linksLength] {
+			if k[i] = b.dict.lookup(a.dict.words[t]); k[i] == unknownToken {
+				known = false
+				break
+			}
+		}
+		if known {
+			other = b.chain[k]
+		}
+		for i, t := range choices.tokens {
+			if other != nil {
+				bt := endToken
+				if t != endToken {
+					bt = b.dict.lookup(a.dict.words[t])
+				}
+				if other.find(bt) != -1 {
+					continue
+				}
+			}
+			missing = append(missing, Transition{
+				Link:   a.dict.link(key, a.linksLength),
+				Suffix: a.dict.words[t],
+				End:    t == endToken,
+				Weight: choices.counts[i],
+			})
+		}
+	}
+	return missing
+}
+
+// Clone returns a copy of the Chain, which can be changed without changing this one.
+// The copy has its own source of randomness, seeded from this one.
+func (c *Chain) Clone() *Chain {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	clone := *c
+	clone.lock = &sync.RWMutex{}
+	clone.dict = &dictionary{
+		ids:   make(map[string]token, len(c.dict.ids)),
+		words: append([]string(nil), c.dict.words...),
+	}
+	for word, t := range c.dict.ids {
+		clone.dict.ids[word] = t
+	}
+	clone.chain = cloneTable(c.chain)
+	clone.reverse = cloneTable(c.reverse)
+	if c.lower != nil {
+		clone.lower = make([]map[linkKey]*suffixes, len(c.lower))
+		for i, table := range c.lower {
+			clone.lower[i] = cloneTable(table)
+		}
+	}
+	clone.rng = rand.New(newLockedSource(rand.NewSource(c.rng.Int63())))
+	return &clone
+}
+
+// cloneTable returns a copy of the given Links and their suffixes
+func cloneTable(m map[linkKey]*suffixes) map[linkKey]*suffixes {
+	clone := make(map[linkKey]*suffixes, len(m))
+	for key, choices := range m {
+		copied := &suffixes{
+			tokens: append([]token(nil), choices.tokens...),
+			counts: append([]float64(nil), choices.counts...),
+			total:  choices.total,
+		}
+		if choices.index != nil {
+			copied.buildIndex()
+		}
+		clone[key] = copied
+	}
+	return clone
+}
@@ -0,0 +1,112 @@
+package gomarkov
+
+import "sort"
+
+// Prune removes every transition with a weight below threshold, and returns how many were removed.
+// Links left without suffixes are removed, as are words that are no longer in any Link.
+func (c *Chain) Prune(threshold float64) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	type transition struct {
+		key    linkKey
+		suffix token
+		count  float64
+	}
+	var pruned []transition
+	for _, key := range sortedKeys(c.chain) {
+		choices := c.chain[key]
+		for i, t := range choices.tokens {
+			if choices.counts[i] < threshold {
+				pruned = append(pruned, transition{key, t, choices.counts[i]})
+			}
+		}
+	}
+	for _, p := range pruned {
+		c.add(p.key, p.suffix, -p.count)
+	}
+	if len(pruned) != 0 {
+		c.compact()
+	}
+	return len(pruned)
+}
+
+// Cap removes the least used Links, the ones with the lowest total weight of suffixes, until the
+// Chain has at most maxLinks Links. Returns how many Links were removed. Links that lead to a removed
+// Link are kept, generation stops there unless backoff is enabled. Words that are no longer in any
+// Link are removed.
+func (c *Chain) Cap(maxLinks int) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if maxLinks < 0 || len(c.chain) <= maxLinks {
+		return 0
+	}
+	keys := sortedKeys(c.chain)
+	// Keep the order of the keys for Links that are used just as much, so that the same Links are always removed
+	sort.SliceStable(keys, func(i, j int) bool { return c.chain[keys[i]].total < c.chain[keys[j]].total })
+	evicted := keys[:len(keys)-maxLinks]
+	for _, key := range evicted {
+		choices := c.chain[key]
+		suffixTokens := append([]token(nil), choices.tokens...)
+		counts := append([]float64(nil), choices.counts...)
+		for i, t := range suffixTokens {
+			c.add(key, t, -counts[i])
+		}
+	}
+	c.compact()
+	return len(evicted)
+}
+
+// compact removes the words that are no longer in any Link or suffix from the dictionary, giving every
+// word left a new token. The reverse map and the backoff tables are rebuilt with the new tokens.
+func (c *Chain) compact() {
+	used := make([]bool, len(c.dict.words))
+	for key, choices := range c.chain {
+		for _, t := range key[:c.linksLength] {
+			used[t] = true
+		}
+		for _, t := range choices.tokens {
+			used[t] = true
+		}
+	}
+	dict := newDictionary()
+	tokens := make([]token, len(c.dict.words))
+	for t, word := range c.dict.words {
+		switch {
+		case t < reservedTokens:
+			tokens[t] = token(t)
+		case used[t]:
+			tokens[t] = dict.intern(word)
+		}
+	}
+	if len(dict.words) == len(c.dict.words) {
+		// Every word is still used
+		return
+	}
+
+	chain := make(map[linkKey]*suffixes, len(c.chain))
+	for key, choices := range c.chain {
+		var k linkKey
+		for i, t := range key[:c.linksLength] {
+			k[i] = tokens[t]
+		}
+		remapped := &suffixes{
+			tokens: make([]token, len(choices.tokens)),
+			counts: choices.counts,
+			total:  choices.total,
+		}
+		for i, t := range choices.tokens {
+			remapped.tokens[i] = tokens[t]
+		}
+		if choices.index != nil {
+			remapped.buildIndex()
+		}
+		chain[k] = remapped
+	}
+	c.chain = chain
+	c.dict = dict
+	c.buildReverse()
+	if c.lower != nil {
+		c.lower = nil
+		c.setBackoff(true)
+	}
+}
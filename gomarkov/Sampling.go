@@ -0,0 +1,76 @@
+package gomarkov
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Sampling changes how the next word is picked from the suffixes of a Link.
+// The zero value picks every suffix in proportion to how many times it appeared.
+type Sampling struct {
+	// Temperature scales the suffix counts before picking. Under 1 makes common suffixes more likely
+	// (more coherent text), over 1 makes rare suffixes more likely (more chaotic text). 0 is the same as 1.
+	Temperature float64
+	// TopK only picks from the K most common suffixes, 0 for all of them. 1 always picks the most common one.
+	TopK int
+	// TopP only picks from the most common suffixes that together make up at least
+	// P (from 0 to 1) of the probability, after Temperature is applied. 0 for all of them.
+	TopP float64
+}
+
+// proportional reports whether the sampling picks suffixes in proportion to their counts
+func (s Sampling) proportional() bool {
+	return (s.Temperature == 0 || s.Temperature == 1) && s.TopK <= 0 && (s.TopP <= 0 || s.TopP >= 1)
+}
+
+// pickWith returns a random suffix using the given sampling. Proportional sampling uses the alias table,
+// anything else takes time linear in the amount of suffixes.
+func (s *suffixes) pickWith(rng *rand.Rand, sampling Sampling) token {
+	if len(s.tokens) == 1 || sampling.proportional() {
+		return s.pick(rng)
+	}
+	// Order the suffixes from most to least common, ties keep the order they were added in
+	order := make([]int, len(s.tokens))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s.counts[order[i]] > s.counts[order[j]] })
+	if sampling.TopK > 0 && sampling.TopK < len(order) {
+		order = order[:sampling.TopK]
+	}
+
+	// Apply the temperature, raising the counts to 1/T is the same as dividing their logarithms by T
+	weights := make([]float64, len(order))
+	total := 0.0
+	for i, position := range order {
+		weights[i] = s.counts[position]
+		if sampling.Temperature > 0 && sampling.Temperature != 1 {
+			// Scale by the largest count first, so that low temperatures don't overflow
+			weights[i] = math.Pow(weights[i]/s.counts[order[0]], 1/sampling.Temperature)
+		}
+		total += weights[i]
+	}
+
+	if sampling.TopP > 0 && sampling.TopP < 1 {
+		kept := 0.0
+		for i, weight := range weights {
+			kept += weight
+			if kept >= sampling.TopP*total {
+				weights, total = weights[:i+1], kept
+				break
+			}
+		}
+	}
+
+	// Pick from whatever is left
+	target := rng.Float64() * total
+	for i, weight := range weights {
+		target -= weight
+		if target < 0 {
+			return s.tokens[order[i]]
+		}
+	}
+	// Rounding errors can leave a tiny bit of the target, that belongs to the last suffix
+	return s.tokens[order[len(weights)-1]]
+}
@@ -0,0 +1,70 @@
+package gomarkov
+
+import "math"
+
+// Score is how likely a sentence is under a Chain
+type Score struct {
+	// LogProb is the natural logarithm of the probability of the sentence
+	LogProb float64
+	// Perplexity is the per-token perplexity of the sentence, the lower it is,
+	// the more the sentence looks like what the Chain was fed
+	Perplexity float64
+	// Tokens is the amount of transitions that were scored, including the end token
+	Tokens int
+	// Unknown is the amount of those transitions that the Chain has never seen
+	Unknown int
+}
+
+// Score returns the log-probability and perplexity of the given words under the Chain.
+// Probabilities are add-one smoothed over the vocabulary, so that a sentence with
+// transitions the Chain has never seen is unlikely, but not impossible. With backoff
+// enabled, unknown Links are scored with the longest shorter Link that has suffixes.
+// Under an empty Chain, which can't generate anything, every sentence is impossible.
+func (c *Chain) Score(words []string) Score {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if len(words) == 0 {
+		return Score{}
+	}
+	if len(c.chain) == 0 {
+		tokens := len(words)
+		if c.endTokens {
+			tokens++
+		}
+		return Score{LogProb: math.Inf(-1), Perplexity: math.Inf(1), Tokens: tokens, Unknown: tokens}
+	}
+	// Every word, plus the end token, could follow any Link
+	vocabulary := float64(len(c.dict.words) - reservedTokens)
+	if c.endTokens {
+		vocabulary++
+	}
+
+	var score Score
+	var k linkKey
+	transition := func(t token) {
+		score.Tokens++
+		choices := c.suffixesOf(k)
+		count := 0.0
+		total := 0.0
+		if choices != nil {
+			if i := choices.find(t); i != -1 {
+				count = choices.counts[i]
+			}
+			total = choices.total
+		}
+		if count == 0 {
+			score.Unknown++
+		}
+		score.LogProb += math.Log((count + 1) / (total + vocabulary))
+	}
+	for _, s := range words {
+		t := c.dict.lookup(s)
+		transition(t)
+		k.shift(t, c.linksLength)
+	}
+	if c.endTokens {
+		transition(endToken)
+	}
+	score.Perplexity = math.Exp(-score.LogProb / float64(score.Tokens))
+	return score
+}
@@ -0,0 +1,264 @@
+package gomarkov
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// snapshotMagic is written at the start of every Chain snapshot
+const snapshotMagic = "GMKV"
+
+// SnapshotVersion is the current version of the Chain snapshot format.
+// Version 1 snapshots, which store every word as a string and have no end tokens, and version 2
+// snapshots, which store suffix counts as integers, can still be loaded.
+const SnapshotVersion = 3
+
+// maxSnapshotString is the longest string a snapshot can hold, a longer one means the snapshot is corrupt
+const maxSnapshotString = 1 << 20
+
+const (
+	// snapshotEndTokens is the snapshot flag for a Chain that records end tokens
+	snapshotEndTokens = 1
+	// snapshotSeparator is the snapshot flag for a Chain with a separator other than a space,
+	// which is written as a string right after the flags
+	snapshotSeparator = 2
+)
+
+var (
+	// ErrBadSnapshot is returned when the data given to Load is not a Chain snapshot
+	ErrBadSnapshot = errors.New("gomarkov: not a chain snapshot")
+	// ErrSnapshotVersion is returned when the snapshot was written by an unknown format version
+	ErrSnapshotVersion = errors.New("gomarkov: unsupported snapshot version")
+)
+
+// Save writes the Chain to the given Writer in a compact, versioned binary format.
+//
+// The format is the magic "GMKV", followed by uvarints for the version, links length and flags,
+// and the separator if it's not a space.
+// Then comes the dictionary: the amount of words, followed by every word except the reserved ones,
+// in token order. Strings are written as a uvarint length and the bytes. Finally, the amount of
+// links, with every link written as its tokens, then the amount of suffixes, then each suffix
+// token with its weight, all as uvarints. Weights are the bits of the float64 with the bytes reversed,
+// which keeps whole numbers, the usual weights, down to a few bytes.
+func (c *Chain) Save(w io.Writer) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	bw := bufio.NewWriter(w)
+	sw := snapshotWriter{w: bw}
+	sw.bytes([]byte(snapshotMagic))
+	sw.uvarint(SnapshotVersion)
+	sw.uvarint(uint64(c.linksLength))
+	var flags uint64
+	if c.endTokens {
+		flags |= snapshotEndTokens
+	}
+	if c.separator != " " {
+		flags |= snapshotSeparator
+	}
+	sw.uvarint(flags)
+	if c.separator != " " {
+		sw.string(c.separator)
+	}
+	words := c.dict.words[reservedTokens:]
+	sw.uvarint(uint64(len(words)))
+	for _, word := range words {
+		sw.string(word)
+	}
+	sw.uvarint(uint64(len(c.chain)))
+	for key, choices := range c.chain {
+		for _, t := range key[:c.linksLength] {
+			sw.uvarint(uint64(t))
+		}
+		sw.uvarint(uint64(len(choices.tokens)))
+		for i, t := range choices.tokens {
+			sw.uvarint(uint64(t))
+			sw.weight(choices.counts[i])
+		}
+	}
+	if sw.err != nil {
+		return sw.err
+	}
+	return bw.Flush()
+}
+
+// Load reads a Chain previously written with Save from the given Reader
+func Load(r io.Reader) (*Chain, error) {
+	sr := snapshotReader{r: bufio.NewReader(r)}
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(sr.r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != snapshotMagic {
+		return nil, ErrBadSnapshot
+	}
+	var c *Chain
+	var err error
+	switch version := sr.uvarint(); {
+	case sr.err != nil:
+		return nil, sr.err
+	case version == 1:
+		c, err = loadVersion1(&sr)
+	case version == 2 || version == SnapshotVersion:
+		c, err = loadWithDictionary(&sr, version)
+	default:
+		return nil, ErrSnapshotVersion
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The reverse map is not stored, as it holds the same transitions
+	c.buildReverse()
+	return c, nil
+}
+
+// loadVersion1 reads the rest of a version 1 snapshot, where every word is stored as a string
+func loadVersion1(sr *snapshotReader) (*Chain, error) {
+	c := NewChain(sr.linksLength())
+	links := sr.uvarint()
+	for i := uint64(0); i < links && sr.err == nil; i++ {
+		var key linkKey
+		for j := 0; j < c.linksLength; j++ {
+			key[j] = c.dict.intern(sr.string())
+		}
+		suffixCount := sr.uvarint()
+		for j := uint64(0); j < suffixCount && sr.err == nil; j++ {
+			t := c.dict.intern(sr.string())
+			addPair(c.chain, key, t, float64(sr.uvarint()))
+		}
+	}
+	return c, sr.err
+}
+
+// loadWithDictionary reads the rest of a version 2 or later snapshot, with a dictionary and tokens
+func loadWithDictionary(sr *snapshotReader, version uint64) (*Chain, error) {
+	c := NewChain(sr.linksLength())
+	flags := sr.uvarint()
+	c.endTokens = flags&snapshotEndTokens != 0
+	if flags&snapshotSeparator != 0 {
+		c.separator = sr.string()
+	}
+	words := sr.uvarint()
+	for i := uint64(0); i < words && sr.err == nil; i++ {
+		c.dict.words = append(c.dict.words, sr.string())
+		c.dict.ids[c.dict.words[len(c.dict.words)-1]] = token(len(c.dict.words) - 1)
+	}
+	links := sr.uvarint()
+	for i := uint64(0); i < links && sr.err == nil; i++ {
+		var key linkKey
+		for j := 0; j < c.linksLength; j++ {
+			key[j] = sr.token(c.dict)
+		}
+		suffixCount := sr.uvarint()
+		for j := uint64(0); j < suffixCount && sr.err == nil; j++ {
+			t := sr.token(c.dict)
+			if version == 2 {
+				addPair(c.chain, key, t, float64(sr.uvarint()))
+			} else {
+				addPair(c.chain, key, t, sr.weight())
+			}
+		}
+	}
+	return c, sr.err
+}
+
+// snapshotWriter writes snapshot values, remembering the first error that occurs
+type snapshotWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (s *snapshotWriter) bytes(b []byte) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.Write(b)
+}
+
+func (s *snapshotWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(s.buf[:], v)
+	s.bytes(s.buf[:n])
+}
+
+func (s *snapshotWriter) weight(v float64) {
+	s.uvarint(bits.ReverseBytes64(math.Float64bits(v)))
+}
+
+func (s *snapshotWriter) string(v string) {
+	s.uvarint(uint64(len(v)))
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.WriteString(v)
+}
+
+// snapshotReader reads snapshot values, remembering the first error that occurs
+type snapshotReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (s *snapshotReader) uvarint() uint64 {
+	if s.err != nil {
+		return 0
+	}
+	var v uint64
+	v, s.err = binary.ReadUvarint(s.r)
+	if s.err == io.EOF {
+		s.err = io.ErrUnexpectedEOF
+	}
+	return v
+}
+
+// weight reads a suffix weight, which has to be a positive number
+func (s *snapshotReader) weight() float64 {
+	w := math.Float64frombits(bits.ReverseBytes64(s.uvarint()))
+	if s.err == nil && !(w > 0 && w <= math.MaxFloat64) {
+		s.err = ErrBadSnapshot
+	}
+	return w
+}
+
+// string reads a string of at most maxSnapshotString bytes. It's read as it comes instead of all at
+// once, so that a corrupt length fails at the end of the snapshot instead of allocating all of it.
+func (s *snapshotReader) string() string {
+	length := s.uvarint()
+	if s.err == nil && length > maxSnapshotString {
+		s.err = ErrBadSnapshot
+	}
+	if s.err != nil {
+		return ""
+	}
+	var b strings.Builder
+	if _, s.err = io.CopyN(&b, s.r, int64(length)); s.err != nil {
+		if s.err == io.EOF {
+			s.err = io.ErrUnexpectedEOF
+		}
+		return ""
+	}
+	return b.String()
+}
+
+// linksLength reads the length of the Links, which has to be between 1 and MaxLinksLength
+func (s *snapshotReader) linksLength() int {
+	length := s.uvarint()
+	if s.err == nil && (length < 1 || length > MaxLinksLength) {
+		s.err = ErrBadSnapshot
+		return 1
+	}
+	return int(length)
+}
+
+// token reads a token, which has to be in the given dictionary
+func (s *snapshotReader) token(d *dictionary) token {
+	t := s.uvarint()
+	if s.err == nil && t >= uint64(len(d.words)) {
+		s.err = ErrBadSnapshot
+	}
+	return token(t)
+}
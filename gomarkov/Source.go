@@ -0,0 +1,29 @@
+package gomarkov
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// lockedSource is a Source that can be used by many goroutines at once, letting generation,
+// which only holds the read lock of the Chain, share the source of randomness of the Chain
+type lockedSource struct {
+	lock sync.Mutex
+	src  rand.Source
+}
+
+func newLockedSource(src rand.Source) *lockedSource {
+	return &lockedSource{src: src}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.src.Seed(seed)
+}
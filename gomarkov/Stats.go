@@ -0,0 +1,80 @@
+package gomarkov
+
+import (
+	"math"
+	"sort"
+)
+
+// Stats describes the size and shape of a Chain
+type Stats struct {
+	// Links is the amount of Links that have suffixes
+	Links int
+	// Transitions is the amount of times any suffix was fed after any Link, end tokens included,
+	// rounded when the Chain was merged with a weight
+	Transitions int
+	// Vocabulary is the amount of distinct words that can be generated
+	Vocabulary int
+	// Branching is the average amount of distinct suffixes a Link has
+	Branching float64
+	// Entropy is the average entropy of the next word in bits, weighted by how often each Link appeared.
+	// 0 means the Chain can only repeat what it was fed, every extra bit doubles the choices.
+	Entropy float64
+	// TopWords are the most frequent words, most frequent first
+	TopWords []WordCount
+}
+
+// WordCount is a word and the amount of times it was fed, rounded when the Chain was merged with a weight
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// Stats returns the statistics of the Chain, with the top most frequent words
+func (c *Chain) Stats(top int) Stats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	stats := Stats{
+		Links: len(c.chain),
+	}
+	counts := make(map[token]float64)
+	distinct := 0
+	transitions := 0.0
+	for _, choices := range c.chain {
+		distinct += len(choices.tokens)
+		transitions += choices.total
+		// Weighting the entropy of every Link by its total makes the Link's own total cancel out
+		for i, t := range choices.tokens {
+			p := choices.counts[i] / choices.total
+			stats.Entropy -= choices.counts[i] * math.Log2(p)
+			if t != endToken {
+				counts[t] += choices.counts[i]
+			}
+		}
+	}
+	stats.Transitions = int(math.Round(transitions))
+	if stats.Links > 0 {
+		stats.Branching = float64(distinct) / float64(stats.Links)
+		stats.Entropy /= transitions
+	}
+	stats.Vocabulary = len(counts)
+
+	// Sort the words from most to least frequent, alphabetically when they're just as frequent
+	words := make([]WordCount, 0, len(counts))
+	for t, count := range counts {
+		words = append(words, WordCount{Word: c.dict.words[t], Count: int(math.Round(count))})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+	if top < 0 {
+		top = 0
+	}
+	if top < len(words) {
+		words = words[:top]
+	}
+	stats.TopWords = words
+	return stats
+}
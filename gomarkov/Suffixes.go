@@ -0,0 +1,177 @@
+package gomarkov
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// indexThreshold is the amount of suffixes after which a word index is kept,
+// below it, looking a word up is a linear scan
+const indexThreshold = 8
+
+// minRemainder is the fraction of its weight under which a suffix that appearances are removed from
+// is removed, so that rounding errors from weighted feeds and merges don't leave suffixes that can
+// practically never be picked. It's relative, so that suffixes fed with tiny weights are still kept.
+const minRemainder = 1e-12
+
+// suffixes contains the words that can follow a Link, and the weight of each word, which is the number
+// of times it appeared unless the Chain was merged with a weight.
+// Words keep the order they were first added in, so that sampling with the same
+// random numbers always gives the same word.
+type suffixes struct {
+	tokens []token
+	counts []float64
+	total  float64
+	// index maps a word to its position, only kept for lists longer than indexThreshold
+	index map[token]int
+	// alias holds the *aliasTable for sampling, nil when it has to be rebuilt. It's built by generation,
+	// which only holds the read lock of the Chain, so it's loaded and stored atomically.
+	alias atomic.Value
+}
+
+// find returns the position of the given word, or -1 if it's not a suffix
+func (s *suffixes) find(word token) int {
+	if s.index != nil {
+		if i, ok := s.index[word]; ok {
+			return i
+		}
+		return -1
+	}
+	for i, w := range s.tokens {
+		if w == word {
+			return i
+		}
+	}
+	return -1
+}
+
+// add adds count appearances of the given word, a negative count removes appearances instead.
+// A word whose count drops to zero, or to a rounding error of what it was, is removed.
+func (s *suffixes) add(word token, count float64) {
+	i := s.find(word)
+	if i == -1 {
+		if count <= 0 {
+			return
+		}
+		i = len(s.tokens)
+		s.tokens = append(s.tokens, word)
+		s.counts = append(s.counts, 0)
+		if s.index != nil {
+			s.index[word] = i
+		} else if len(s.tokens) > indexThreshold {
+			s.buildIndex()
+		}
+	}
+	if count < 0 && s.counts[i]+count <= s.counts[i]*minRemainder {
+		count = -s.counts[i]
+	}
+	s.counts[i] += count
+	s.total += count
+	s.resetAlias()
+	if s.counts[i] == 0 {
+		s.remove(i)
+		if len(s.tokens) == 0 {
+			// Don't let rounding errors build up in the total of an empty list
+			s.total = 0
+		}
+	}
+}
+
+// remove removes the word at position i, moving the last word into its place
+func (s *suffixes) remove(i int) {
+	last := len(s.tokens) - 1
+	if s.index != nil {
+		delete(s.index, s.tokens[i])
+		if i != last {
+			s.index[s.tokens[last]] = i
+		}
+	}
+	s.tokens[i], s.counts[i] = s.tokens[last], s.counts[last]
+	s.tokens, s.counts = s.tokens[:last], s.counts[:last]
+}
+
+func (s *suffixes) buildIndex() {
+	s.index = make(map[token]int, len(s.tokens))
+	for i, w := range s.tokens {
+		s.index[w] = i
+	}
+}
+
+// pick returns a random suffix, weighted by its count, in constant time.
+// The alias table is rebuilt first if the suffixes changed since the last pick.
+func (s *suffixes) pick(rng *rand.Rand) token {
+	if len(s.tokens) == 1 {
+		return s.tokens[0]
+	}
+	alias, _ := s.alias.Load().(*aliasTable)
+	if alias == nil {
+		// Generating at the same time might build the same table twice, either one can be kept
+		alias = newAliasTable(s.counts, s.total)
+		s.alias.Store(alias)
+	}
+	return s.tokens[alias.sample(rng)]
+}
+
+// resetAlias makes the next pick rebuild the alias table, the caller should hold the write lock
+func (s *suffixes) resetAlias() {
+	if alias, _ := s.alias.Load().(*aliasTable); alias != nil {
+		s.alias.Store((*aliasTable)(nil))
+	}
+}
+
+// aliasTable is a Walker/Vose alias table, which samples from a weighted distribution in constant time
+type aliasTable struct {
+	prob  []float64
+	alias []int
+}
+
+// newAliasTable creates an alias table for the given counts, which add up to total
+func newAliasTable(counts []float64, total float64) *aliasTable {
+	n := len(counts)
+	t := &aliasTable{
+		prob:  make([]float64, n),
+		alias: make([]int, n),
+	}
+	// Scale the probabilities so that the average is 1, then split them
+	// into the ones under and over the average
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, count := range counts {
+		scaled[i] = count * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	// Fill every small column up to 1 with a piece of a large one
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		t.prob[l] = scaled[l]
+		t.alias[l] = g
+		scaled[g] = scaled[g] + scaled[l] - 1
+		if scaled[g] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, g)
+		}
+	}
+	// Whatever is left is 1, give or take rounding errors
+	for _, i := range large {
+		t.prob[i] = 1
+	}
+	for _, i := range small {
+		t.prob[i] = 1
+	}
+	return t
+}
+
+// sample returns a random position in the table
+func (t *aliasTable) sample(rng *rand.Rand) int {
+	i := rng.Intn(len(t.prob))
+	if rng.Float64() < t.prob[i] {
+		return i
+	}
+	return t.alias[i]
+}
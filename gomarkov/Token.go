@@ -0,0 +1,89 @@
+package gomarkov
+
+// MaxLinksLength is the largest amount of words a Link of a Chain can have
+const MaxLinksLength = 6
+
+// token is the ID of a word interned in a dictionary. The empty word, which stands for
+// the start of a message, is always 0, and the end of a message is always endToken.
+type token uint32
+
+// endToken is the token recorded after the last word of a message
+const endToken token = 1
+
+// reservedTokens is the amount of tokens that do not stand for a word
+const reservedTokens = 2
+
+// unknownToken is never given to a word, it's used for words that are not in the dictionary
+const unknownToken = ^token(0)
+
+// linkKey is a Link stored as a fixed-size tuple of tokens, for use as a map key.
+// A Chain only uses the first linksLength tokens, the rest are always 0.
+type linkKey [MaxLinksLength]token
+
+// shift removes the first token from the first n tokens of the key, and appends the given token
+func (k *linkKey) shift(t token, n int) {
+	copy(k[:n], k[1:n])
+	k[n-1] = t
+}
+
+// less reports whether the key sorts before the other one
+func (k linkKey) less(other linkKey) bool {
+	for i := range k {
+		if k[i] != other[i] {
+			return k[i] < other[i]
+		}
+	}
+	return false
+}
+
+// dictionary interns every word of a Chain, so that each word is only stored once
+// no matter how many links and suffixes it appears in. Words are never removed.
+type dictionary struct {
+	ids   map[string]token
+	words []string
+}
+
+func newDictionary() *dictionary {
+	return &dictionary{
+		// The end token has no word, so it can never be looked up
+		ids:   map[string]token{"": 0},
+		words: []string{"", ""},
+	}
+}
+
+// intern returns the token of the given word, adding it to the dictionary if needed
+func (d *dictionary) intern(word string) token {
+	if t, ok := d.ids[word]; ok {
+		return t
+	}
+	t := token(len(d.words))
+	d.ids[word] = t
+	d.words = append(d.words, word)
+	return t
+}
+
+// lookup returns the token of the given word, or unknownToken if the word is not in the dictionary
+func (d *dictionary) lookup(word string) token {
+	if t, ok := d.ids[word]; ok {
+		return t
+	}
+	return unknownToken
+}
+
+// link returns the words of the first n tokens of the given key
+func (d *dictionary) link(k linkKey, n int) Link {
+	l := make(Link, n)
+	for i := range l {
+		l[i] = d.words[k[i]]
+	}
+	return l
+}
+
+// text returns the given tokens as words
+func (d *dictionary) text(tokens []token) []string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = d.words[t]
+	}
+	return words
+}
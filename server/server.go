@@ -3,11 +3,15 @@ package server
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/wallnutkraken/gotuskgo/bot"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/dbwrap"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/serial"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
@@ -21,8 +25,14 @@ type Server struct {
 	logs          []serial.LogLine
 	logLock       *sync.Mutex
 	nextMessageAt int64
-	settingsLock  *sync.Mutex
-	tuskLogs      chan serial.LogLine
+	nextSnapshot  int64
+	nextPrune     int64
+	// snapshotMinutes and pruneMinutes are the intervals nextSnapshot and nextPrune were set with,
+	// so that the timers start over when the settings change them
+	snapshotMinutes int
+	pruneMinutes    int
+	settingsLock    *sync.Mutex
+	tuskLogs        chan serial.LogLine
 }
 
 // AllLogs returns every log stored in ther server's memory
@@ -32,15 +42,28 @@ func (s *Server) AllLogs() []serial.LogLine {
 	return s.logs
 }
 
-// SetSettings sets the settings for all the underlying objects
+// SetSettings sets the settings for all the underlying objects and saves them to the settings file.
+// Settings the bot rejects are neither kept nor saved.
 func (s *Server) SetSettings(cfg settings.Application) error {
-	// Update the settings inside this object
 	s.settingsLock.Lock()
-	s.config = cfg
-	s.settingsLock.Unlock()
+	defer s.settingsLock.Unlock()
+	return s.applySettings(cfg)
+}
 
-	// Propogate it downwards to the bot
-	return s.tusk.UpdateSettings(cfg)
+// applySettings checks the given settings, propogates them downwards, and only then replaces the
+// settings of this object and saves them. The caller should hold the settings lock.
+func (s *Server) applySettings(cfg settings.Application) error {
+	if err := tuskbrain.CheckSettings(cfg.Brain); err != nil {
+		return errors.WithMessage(err, "CheckSettings")
+	}
+	if _, err := tuskbrain.NewFilter(cfg.Filter); err != nil {
+		return errors.WithMessage(err, "NewFilter")
+	}
+	if err := s.tusk.UpdateSettings(cfg); err != nil {
+		return err
+	}
+	s.config = cfg
+	return errors.Wrap(settings.Save(cfg), "save")
 }
 
 // AddMessages adds the given array of messages to the database and the markov chain
@@ -48,6 +71,101 @@ func (s *Server) AddMessages(msgs []string) error {
 	return s.tusk.AddMessages(msgs)
 }
 
+// BlendCorpus blends the messages read from the given Reader into the markov chain with the given weight,
+// without storing them. Returns the amount of messages blended, and of transitions the chain didn't have before.
+func (s *Server) BlendCorpus(r io.Reader, delimiter string, weight float64) (int, int, error) {
+	return s.tusk.BlendCorpus(r, delimiter, weight)
+}
+
+// RemoveMessages removes the given array of messages from the database and the markov chain
+func (s *Server) RemoveMessages(msgs []string) error {
+	return s.tusk.RemoveMessages(msgs)
+}
+
+// BrainStats returns the statistics of the markov chain, with the top most frequent words
+func (s *Server) BrainStats(top int) gomarkov.Stats {
+	return s.tusk.BrainStats(top)
+}
+
+// Regenerate makes up a message sent out earlier again, from the seed it was logged with and the
+// Telegram chat it was sent to, 0 for the messages sent to every chat
+func (s *Server) Regenerate(chatID int64, seed int64) string {
+	return s.tusk.Regenerate(chatID, seed)
+}
+
+// ExportBrain writes the markov chain to the given Writer in the given format, either all of it
+// or just the neighbourhood of the given word up to the given depth
+func (s *Server) ExportBrain(w io.Writer, format gomarkov.ExportFormat, word string, depth int) error {
+	return s.tusk.ExportBrain(w, format, word, depth)
+}
+
+// Blocklist returns the blocked words and patterns of the content filter
+func (s *Server) Blocklist() settings.Filter {
+	s.settingsLock.Lock()
+	defer s.settingsLock.Unlock()
+	return s.config.Filter
+}
+
+// BlockTerms adds the given words and patterns to the blocklist, and saves it to the settings file
+func (s *Server) BlockTerms(words, patterns []string) error {
+	return s.updateBlocklist(func(filter *settings.Filter) {
+		filter.Words = addTerms(filter.Words, words)
+		filter.Patterns = addTerms(filter.Patterns, patterns)
+	})
+}
+
+// UnblockTerms removes the given words and patterns from the blocklist, and saves it to the settings file
+func (s *Server) UnblockTerms(words, patterns []string) error {
+	return s.updateBlocklist(func(filter *settings.Filter) {
+		filter.Words = removeTerms(filter.Words, words)
+		filter.Patterns = removeTerms(filter.Patterns, patterns)
+	})
+}
+
+// updateBlocklist changes a copy of the blocklist with the given function and propogates it downwards,
+// saving it only once the bot accepted its patterns. The settings stay locked throughout, so that
+// concurrent updates don't overwrite each other.
+func (s *Server) updateBlocklist(update func(filter *settings.Filter)) error {
+	s.settingsLock.Lock()
+	defer s.settingsLock.Unlock()
+	cfg := s.config
+	// The lists are shared with the current settings, copy them before changing them
+	cfg.Filter.Words = append([]string{}, cfg.Filter.Words...)
+	cfg.Filter.Patterns = append([]string{}, cfg.Filter.Patterns...)
+	update(&cfg.Filter)
+	return s.applySettings(cfg)
+}
+
+// addTerms appends the given terms to the list, leaving out empty ones and ones already in it
+func addTerms(list []string, terms []string) []string {
+	for _, term := range terms {
+		if term != "" && !containsTerm(list, term) {
+			list = append(list, term)
+		}
+	}
+	return list
+}
+
+// removeTerms returns the list without any of the given terms
+func removeTerms(list []string, terms []string) []string {
+	kept := []string{}
+	for _, term := range list {
+		if !containsTerm(terms, term) {
+			kept = append(kept, term)
+		}
+	}
+	return kept
+}
+
+func containsTerm(list []string, term string) bool {
+	for _, t := range list {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
+
 // GetGlobalSettings returns the global application settings
 func (s *Server) GetGlobalSettings() settings.Application {
 	return s.config
@@ -106,11 +224,42 @@ func (s *Server) setNextMessageTime() {
 	s.nextMessageAt = time.Now().Add(minutesUntilNext).Unix()
 }
 
+// intervals returns the minutes between snapshots and between prunes in the current settings
+func (s *Server) intervals() (int, int) {
+	s.settingsLock.Lock()
+	defer s.settingsLock.Unlock()
+	return s.config.Database.GetSnapshotMinutes(), s.config.Brain.PruneMinutes
+}
+
+func (s *Server) setNextSnapshotTime() {
+	minutes, _ := s.intervals()
+	s.snapshotMinutes = minutes
+	if minutes <= 0 {
+		// Periodic snapshots are disabled
+		s.nextSnapshot = 0
+		return
+	}
+	s.nextSnapshot = time.Now().Add(time.Minute * time.Duration(minutes)).Unix()
+}
+
+func (s *Server) setNextPruneTime() {
+	_, minutes := s.intervals()
+	s.pruneMinutes = minutes
+	if minutes <= 0 {
+		// Pruning is disabled
+		s.nextPrune = 0
+		return
+	}
+	s.nextPrune = time.Now().Add(time.Minute * time.Duration(minutes)).Unix()
+}
+
 // Start the GoTuskGo bot instance
 //
 // This is a blocking call
 func (s *Server) Start() {
 	s.setNextMessageTime()
+	s.setNextSnapshotTime()
+	s.setNextPruneTime()
 	for {
 		if err := s.tusk.GetMessagesTelegram(); err != nil {
 			// Add it to the application errors for remote logging
@@ -124,6 +273,28 @@ func (s *Server) Start() {
 			}
 			s.setNextMessageTime()
 		}
+		// The settings can change the intervals at any time, start the timers over when they do
+		snapshotMinutes, pruneMinutes := s.intervals()
+		if snapshotMinutes != s.snapshotMinutes {
+			s.setNextSnapshotTime()
+		}
+		if pruneMinutes != s.pruneMinutes {
+			s.setNextPruneTime()
+		}
+		if s.nextSnapshot != 0 && s.nextSnapshot <= time.Now().Unix() {
+			// Save the brain, so that a restart doesn't have to replay every message
+			if err := s.tusk.SaveSnapshot(); err != nil {
+				s.LogError(err)
+			}
+			s.setNextSnapshotTime()
+		}
+		if s.nextPrune != 0 && s.nextPrune <= time.Now().Unix() {
+			// Keep the brain from growing forever
+			if err := s.tusk.PruneBrain(); err != nil {
+				s.LogError(err)
+			}
+			s.setNextPruneTime()
+		}
 
 		time.Sleep(time.Millisecond * 500)
 	}
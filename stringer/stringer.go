@@ -1,6 +1,12 @@
 // Package stringer contains various string utilities not in strings/strconv
 package stringer
 
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
 // SplitMultiple splits on a multi-character list (splitChars)
 func SplitMultiple(v string, splitChars string) []string {
 	// Create a rune slice from splitChars
@@ -36,3 +42,94 @@ func inRuneSlice(v rune, s []rune) bool {
 	}
 	return false
 }
+
+// closingPunctuation is punctuation that sticks to the word before it
+const closingPunctuation = ".,!?;:…%)]}»”’"
+
+// openingPunctuation is punctuation that sticks to the word after it
+const openingPunctuation = "([{«“‘¿¡"
+
+// Tokenize splits a message into words and punctuation, keeping every run of punctuation as a token of
+// its own, so that it can be put back with Detokenize. Punctuation between two letters or digits, such
+// as in "don't" or "3.14", is part of the word, and so is anything in a link.
+func Tokenize(v string) []string {
+	result := []string{}
+	for _, field := range strings.Fields(v) {
+		if strings.Contains(field, "://") {
+			result = append(result, field)
+			continue
+		}
+		runes := []rune(field)
+		start := 0
+		for i := range runes {
+			if i == 0 || isPunctuation(runes, i) == isPunctuation(runes, i-1) {
+				continue
+			}
+			// The token changes from a word to punctuation, or the other way around
+			result = append(result, string(runes[start:i]))
+			start = i
+		}
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+// isPunctuation reports whether the rune at position i is punctuation that is not part of a word
+func isPunctuation(runes []rune, i int) bool {
+	if !unicode.IsPunct(runes[i]) {
+		return false
+	}
+	inWord := i > 0 && i < len(runes)-1 && isWordRune(runes[i-1]) && isWordRune(runes[i+1])
+	return !inWord
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// IsPunctuation reports whether the given token is made of punctuation only
+func IsPunctuation(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if !unicode.IsPunct(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Detokenize joins the given tokens, made by Tokenize, back into a message, putting spaces between words
+// but not before closing punctuation such as "." or ")", or after opening punctuation such as "(".
+// Straight quotes open and close in turns.
+func Detokenize(tokens []string) string {
+	var sb strings.Builder
+	attach := true
+	openQuotes := map[rune]bool{}
+	for _, token := range tokens {
+		opens, closes := false, false
+		if IsPunctuation(token) {
+			first, _ := utf8.DecodeRuneInString(token)
+			last, _ := utf8.DecodeLastRuneInString(token)
+			switch {
+			case first == '"' || first == '\'':
+				// A quote closes the one before it, or opens a new one
+				closes = openQuotes[first]
+				opens = !closes
+				openQuotes[first] = !closes
+			case strings.ContainsRune(closingPunctuation, first):
+				closes = true
+			}
+			if strings.ContainsRune(openingPunctuation, last) {
+				opens = true
+			}
+		}
+		if !attach && !closes {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(token)
+		attach = opens
+	}
+	return sb.String()
+}
@@ -2,33 +2,384 @@
 package tuskbrain
 
 import (
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
 	"github.com/wallnutkraken/gotuskgo/gomarkov"
 	"github.com/wallnutkraken/gotuskgo/stringer"
 	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
 )
 
-// Brain contains the GoTuskBot brain and associated generation functions
+// maxNameLength is the most characters GenerateName makes a word out of
+const maxNameLength = 20
+
+// Brain contains the GoTuskBot brain and associated generation functions, it's the Markov chain Generator.
+// It is safe for concurrent use, generating only waits for feeding, not for other generation.
 type Brain struct {
+	// lock guards everything below, copies of the brain share it, except for the ones made by Clone
+	lock   *sync.RWMutex
 	chain  *gomarkov.Chain
-	config settings.Brain
+	config *settings.Brain
+	// sources remembers the fed messages for the quality filters, nil if no filter needs them
+	sources *sources
+	// decay weighs messages by how recently they were received, nil if they all weigh the same
+	decay *decay
+	// names is the character chain fed every word, for making up words, nil if it's disabled
+	names *gomarkov.Chain
 }
 
 // New creates a new instance of the TUSK brain
 func New(brainSettings settings.Brain) Brain {
+	return newBrain(gomarkov.NewChain(brainSettings.ChainLength), newNames(brainSettings), brainSettings)
+}
+
+// NewWithSource creates a new instance of the TUSK brain which uses the given Source
+// for all random choices, making its generation reproducible
+func NewWithSource(brainSettings settings.Brain, src rand.Source) Brain {
+	chain := gomarkov.NewChainWithSource(brainSettings.ChainLength, src)
+	names := newNames(brainSettings)
+	if names != nil {
+		names.SetSource(rand.NewSource(src.Int63()))
+	}
+	return newBrain(chain, names, brainSettings)
+}
+
+func newBrain(chain, names *gomarkov.Chain, brainSettings settings.Brain) Brain {
+	chain.SetEndTokens(brainSettings.EndTokens)
+	chain.SetBackoff(brainSettings.Backoff)
+	chain.SetSampling(sampling(brainSettings))
 	return Brain{
-		chain:  gomarkov.NewChain(brainSettings.ChainLength),
-		config: brainSettings,
+		lock:    &sync.RWMutex{},
+		chain:   chain,
+		config:  &brainSettings,
+		sources: newSources(brainSettings),
+		decay:   newDecay(brainSettings, time.Now().Unix()),
+		names:   names,
+	}
+}
+
+// newNames creates the character chain for the given settings, nil if it's disabled
+func newNames(brainSettings settings.Brain) *gomarkov.Chain {
+	if brainSettings.NameLength <= 0 {
+		return nil
 	}
+	return gomarkov.NewCharChain(brainSettings.NameLength)
 }
 
-// Feed feeds the given messages to the bot markov chain
+// sampling returns the sampling set in the given settings
+func sampling(brainSettings settings.Brain) gomarkov.Sampling {
+	return gomarkov.Sampling{
+		Temperature: brainSettings.Temperature,
+		TopK:        brainSettings.TopK,
+		TopP:        brainSettings.TopP,
+	}
+}
+
+// NeedsRebuild reports whether changing the brain settings from old to new changes how the brain
+// is fed, meaning that the brain has to be fed every message again for the new settings to apply
+func NeedsRebuild(old, new settings.Brain) bool {
+	// SplitChars is ignored when punctuation is kept
+	return old.Generator != new.Generator || old.KeepPunctuation != new.KeepPunctuation ||
+		(!new.KeepPunctuation && old.SplitChars != new.SplitChars) || old.Normalize != new.Normalize ||
+		old.ChainLength != new.ChainLength || old.EndTokens != new.EndTokens ||
+		old.HalfLifeDays != new.HalfLifeDays || old.NameLength != new.NameLength ||
+		!newSources(old).equal(newSources(new))
+}
+
+// UpdateSettings replaces the brain settings. Settings that change how the brain is fed,
+// such as the chain length, only apply to what is fed afterwards, the brain should be rebuilt for those.
+func (b Brain) UpdateSettings(brainSettings settings.Brain) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.chain.SetBackoff(brainSettings.Backoff)
+	b.chain.SetSampling(sampling(brainSettings))
+	*b.config = brainSettings
+}
+
+// WithSampling returns the brain generating with the given sampling instead of the one in the
+// settings, for commands that want more coherent or more chaotic messages than usual
+func (b Brain) WithSampling(sampling gomarkov.Sampling) Brain {
+	b.chain = b.chain.WithSampling(sampling)
+	return b
+}
+
+// Feed feeds the given messages, received just now, to the bot markov chain
 func (b Brain) Feed(messages ...string) {
+	b.FeedAt(time.Now().Unix(), messages...)
+}
+
+// FeedAt feeds the given messages, received at the given Unix time, to the bot markov chain.
+// With a half-life set, newer messages weigh more than older ones. Returns the amount of links they added.
+func (b Brain) FeedAt(unix int64, messages ...string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.decay.weight(unix) > maxDecayWeight {
+		b.age(unix)
+	}
+	weight := b.decay.weight(unix)
+	links := 0
+	for _, msg := range messages {
+		words := tokenize(b.config, msg)
+		links += b.chain.FeedWeighted(words, weight)
+		b.sources.add(words, 1)
+		if b.names != nil {
+			for _, word := range words {
+				if !stringer.IsPunctuation(word) {
+					b.names.FeedWeighted(gomarkov.Runes(word), weight)
+				}
+			}
+		}
+	}
+	return links
+}
+
+// FeedFrom feeds every message in the given Reader to the bot markov chain, as if they were received
+// just now. Messages are separated by the given delimiter, or by newlines if it's empty, and are read one
+// at a time, so the Reader can be of any size. Messages skip returns true for are left out, a nil skip
+// leaves out nothing. Returns the amount of messages fed.
+func (b Brain) FeedFrom(r io.Reader, delimiter string, skip func(message string) bool) (int, error) {
+	now := time.Now().Unix()
+	fed := 0
+	err := gomarkov.ReadMessages(r, delimiter, func(message string) {
+		if skip == nil || !skip(message) {
+			b.FeedAt(now, message)
+			fed++
+		}
+	})
+	return fed, err
+}
+
+// Unfeed removes the given messages, fed just now, from the bot markov chain, as if they were never fed
+func (b Brain) Unfeed(messages ...string) {
+	b.UnfeedAt(time.Now().Unix(), messages...)
+}
+
+// UnfeedAt removes the given messages, received at the given Unix time, from the bot markov chain,
+// as if they were never fed
+func (b Brain) UnfeedAt(unix int64, messages ...string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	weight := b.decay.weight(unix)
 	for _, msg := range messages {
-		b.chain.Feed(stringer.SplitMultiple(msg, b.config.SplitChars))
+		words := tokenize(b.config, msg)
+		b.chain.UnfeedWeighted(words, weight)
+		b.sources.add(words, -1)
+		if b.names != nil {
+			for _, word := range words {
+				if !stringer.IsPunctuation(word) {
+					b.names.UnfeedWeighted(gomarkov.Runes(word), weight)
+				}
+			}
+		}
+	}
+}
+
+// Age makes a message received at the given Unix time weigh 1, lowering the weights of everything
+// fed before it, so that the PruneBelow setting is compared to the weight of a message received then.
+// It doesn't change generation, and does nothing without a half-life set.
+func (b Brain) Age(unix int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.age(unix)
+}
+
+// age is Age, the caller should hold the write lock
+func (b Brain) age(unix int64) {
+	if b.decay == nil {
+		return
+	}
+	factor := 1 / b.decay.weight(unix)
+	b.chain.Scale(factor)
+	if b.names != nil {
+		b.names.Scale(factor)
+	}
+	b.decay.epoch = unix
+}
+
+// Reset forgets everything fed to the bot brain, keeping its settings
+func (b Brain) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.chain.Reset()
+	if b.names != nil {
+		b.names.Reset()
+	}
+	b.sources.reset()
+	if b.decay != nil {
+		b.decay.epoch = time.Now().Unix()
 	}
 }
 
-// Generate creates a new string from the bot brain
+// Generate creates a new string from the bot brain. When the Candidates setting is above 1, that many
+// strings are generated and the best one by the quality settings is returned.
 func (b Brain) Generate() string {
-	return b.chain.Generate(b.config.MaxGeneratedLength)
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	generated, _ := b.generateSeeded()
+	return generated
+}
+
+// GenerateSeeded creates a new string from the bot brain, along with the seed it was generated
+// with, so that the same string can be created again with Regenerate
+func (b Brain) GenerateSeeded() (string, int64) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.generateSeeded()
+}
+
+// generateSeeded is GenerateSeeded, the caller should hold the read lock
+func (b Brain) generateSeeded() (string, int64) {
+	return b.pickBest(func() (string, int64) {
+		return b.chain.GenerateSeeded(b.config.MaxGeneratedLength)
+	})
+}
+
+// Regenerate creates a string from the bot brain using the given seed. As long as nothing was fed
+// to the brain since, this is the same string that GenerateSeeded returned with that seed.
+func (b Brain) Regenerate(seed int64) string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return detokenizeText(b.config, b.chain.GenerateWithSeed(b.config.MaxGeneratedLength, seed))
+}
+
+// GenerateFrom creates a new string from the bot brain about the given topic, which is used as the
+// start of the message. Falls back to Generate if the topic is empty or unknown to the brain.
+func (b Brain) GenerateFrom(topic string) string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	seed := tokenize(b.config, topic)
+	if len(seed) == 0 {
+		generated, _ := b.generateSeeded()
+		return generated
+	}
+	generated, _ := b.pickBest(func() (string, int64) {
+		return b.chain.GenerateFrom(seed, b.config.MaxGeneratedLength), 0
+	})
+	if generated == "" {
+		generated, _ = b.generateSeeded()
+	}
+	return generated
+}
+
+// GenerateAround creates a new string from the bot brain that contains the given word anywhere in it,
+// normalized the way fed messages are. Falls back to Generate if the word is unknown to the brain.
+func (b Brain) GenerateAround(word string) string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	word = tokenizeWord(b.config, word)
+	generated, _ := b.pickBest(func() (string, int64) {
+		return b.chain.GenerateAround(word, b.config.MaxGeneratedLength), 0
+	})
+	if generated == "" {
+		generated, _ = b.generateSeeded()
+	}
+	return generated
+}
+
+// GenerateName makes up a new word out of the characters of every word fed to the bot brain, starting
+// with the given prefix, normalized the way fed words are, when a fed word does. Returns an empty string
+// if the NameLength setting is 0, or if the prefix ends with a character that no fed word has.
+func (b Brain) GenerateName(prefix string) string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if b.names == nil {
+		return ""
+	}
+	prefix = tokenizeWord(b.config, prefix)
+	if prefix == "" {
+		return b.names.Generate(maxNameLength)
+	}
+	return b.names.GenerateFrom(gomarkov.Runes(prefix), maxNameLength)
+}
+
+// Score returns how likely the given message is to have been generated by the bot brain
+func (b Brain) Score(message string) gomarkov.Score {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.chain.Score(tokenize(b.config, message))
+}
+
+// Stats returns the statistics of the bot brain, with the top most frequent words
+func (b Brain) Stats(top int) gomarkov.Stats {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.chain.Stats(top)
+}
+
+// Merge adds everything the other brain was fed to this one, weighted by the given weight, so that
+// a curated corpus can be blended in. Merged messages are not in the database, so they are lost
+// when the brain is rebuilt. The other brain needs to have the same chain length.
+// Two brains should not be merged into each other at the same time, as they would wait for each other.
+// With a half-life set, the messages of the other brain weigh as much as ones received just now.
+func (b Brain) Merge(other Brain, weight float64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if other.lock != b.lock {
+		other.lock.RLock()
+		defer other.lock.RUnlock()
+	}
+	now := time.Now().Unix()
+	return b.chain.Merge(other.chain, weight*b.decay.weight(now)/other.decay.weight(now))
+}
+
+// Diff returns the transitions that are only in one of the two brains
+func (b Brain) Diff(other Brain) (gomarkov.Diff, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if other.lock != b.lock {
+		other.lock.RLock()
+		defer other.lock.RUnlock()
+	}
+	return b.chain.Diff(other.chain)
+}
+
+// Clone returns a copy of the brain, which can be fed without changing this one
+func (b Brain) Clone() Brain {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	config := *b.config
+	clone := Brain{
+		lock:    &sync.RWMutex{},
+		chain:   b.chain.Clone(),
+		config:  &config,
+		sources: b.sources.clone(),
+		decay:   b.decay.clone(),
+	}
+	if b.names != nil {
+		clone.names = b.names.Clone()
+	}
+	return clone
+}
+
+// Export writes the bot brain to the given Writer in the given format, either all of it
+// or just the neighbourhood of the given word, normalized the way fed messages are, up to the given depth
+func (b Brain) Export(w io.Writer, format gomarkov.ExportFormat, word string, depth int) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if word != "" {
+		// Look the word up the way it was fed, nothing left of it means it was never fed
+		if word = tokenizeWord(b.config, word); word == "" {
+			return gomarkov.ErrUnknownWord
+		}
+	}
+	return b.chain.Export(w, format, word, depth)
+}
+
+// Prune removes the transitions fed fewer times than the PruneBelow setting, then the least used links
+// until the brain has at most MaxLinks of them. Returns the amount of transitions and links removed.
+// With a half-life set, the brain is aged to the current time first.
+func (b Brain) Prune() (int, int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.age(time.Now().Unix())
+	transitions, links := 0, 0
+	if b.config.PruneBelow > 0 {
+		transitions = b.chain.Prune(b.config.PruneBelow)
+	}
+	if b.config.MaxLinks > 0 {
+		links = b.chain.Cap(b.config.MaxLinks)
+	}
+	return transitions, links
 }
@@ -0,0 +1,570 @@
+package tuskbrain
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
+	"github.com/wallnutkraken/gotuskgo/stringer"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+func Test_Best_candidate_is_not_a_copy(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.Candidates = 20
+	brainSettings.MinWords = 3
+	brainSettings.RejectCopies = true
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	brain.Feed("the cat sat on the mat", "the dog sat on the log", "a cat ate the fish")
+	for i := 0; i < 20; i++ {
+		generated := brain.Generate()
+		switch generated {
+		case "the cat sat on the mat", "the dog sat on the log", "a cat ate the fish":
+			t.Fatalf("generated a copy of a fed message: %q", generated)
+		}
+		if !brain.judge(generated, 0).passes {
+			t.Fatalf("generated %q, which does not pass the filters", generated)
+		}
+	}
+}
+
+func Test_Old_messages_decay_until_they_are_pruned(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.HalfLifeDays = 1
+	brainSettings.PruneBelow = 0.5
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	now := time.Now().Unix()
+	// Two days old, so a quarter of the weight of a new message
+	brain.FeedAt(now-2*24*60*60, "old in joke")
+	brain.FeedAt(now, "fresh meme")
+	if transitions, _ := brain.Prune(); transitions != 4 {
+		t.Fatalf("pruned %d transitions, expected the 4 of the old message", transitions)
+	}
+	if generated := brain.Generate(); generated != "fresh meme" {
+		t.Fatalf("generated %q, expected only the new message to be left", generated)
+	}
+}
+
+func Test_Very_old_messages_are_kept_until_they_are_pruned(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.HalfLifeDays = 1
+	brainSettings.PruneBelow = 0.5
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	now := time.Now().Unix()
+	// 40 half-lives old, so it weighs less than a trillionth of a new message
+	brain.FeedAt(now-40*24*60*60, "ancient in joke")
+	if stats := brain.Stats(0); stats.Links != 4 {
+		t.Fatalf("stats %+v, expected the 4 links of the old message", stats)
+	}
+	if generated := brain.Generate(); generated != "ancient in joke" {
+		t.Fatalf("generated %q, expected the old message", generated)
+	}
+	// And too old for its weight to fit in a float64
+	brain.FeedAt(now-2000*24*60*60, "prehistoric meme")
+	if generated := brain.GenerateAround("prehistoric"); generated != "prehistoric meme" {
+		t.Fatalf("generated %q, expected the oldest message", generated)
+	}
+
+	if transitions, _ := brain.Prune(); transitions != 7 {
+		t.Errorf("pruned %d transitions, expected the 7 of the old messages", transitions)
+	}
+	if stats := brain.Stats(0); stats.Links != 0 {
+		t.Errorf("stats %+v after pruning, expected nothing to be left", stats)
+	}
+}
+
+func Test_Brain_can_be_fed_while_generating(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.Candidates = 3
+	brainSettings.RejectCopies = true
+	brainSettings.HalfLifeDays = 1
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	brain.Feed("the cat sat on the mat", "the dog sat on the log")
+	now := time.Now().Unix()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				brain.FeedAt(now+int64(i*j), "a cat ate the fish")
+				brain.UnfeedAt(now+int64(i*j), "a cat ate the fish")
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				brain.Generate()
+				brain.GenerateAround("cat")
+				brain.GenerateName("c")
+				brain.WithSampling(gomarkov.Sampling{TopK: 1}).GenerateFrom("the")
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 10; j++ {
+			brain.UpdateSettings(brainSettings)
+			brain.Prune()
+			brain.Stats(3)
+			brain.Clone().Feed("a cloned message")
+		}
+	}()
+	wg.Wait()
+	if generated := brain.Generate(); generated == "" {
+		t.Error("nothing was left to generate after feeding concurrently")
+	}
+}
+
+func Test_Chat_brains_are_used_once_they_are_fed_enough(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.ChatBrains = true
+	brainSettings.MinChatMessages = 2
+	chats := NewChats(brainSettings)
+	now := time.Now().Unix()
+	chats.FeedAt("work", now, "the deadline is tomorrow")
+	if _, ok := chats.Brain("work"); ok {
+		t.Fatal("used the brain of a chat with fewer messages than MinChatMessages")
+	}
+	chats.FeedAt("work", now, "the deadline moved")
+	chats.FeedAt("games", now, "gg no re", "git gud")
+	chats.FeedAt("", now, "not from any chat")
+	brain, ok := chats.Brain("work")
+	if !ok {
+		t.Fatal("did not use the brain of a chat with enough messages")
+	}
+	for i := 0; i < 10; i++ {
+		if generated := brain.Generate(); !strings.HasPrefix(generated, "the deadline") {
+			t.Fatalf("generated %q, which is not from the messages of the chat", generated)
+		}
+	}
+	if _, ok := chats.Brain(""); ok {
+		t.Error("kept a brain for messages that are not from a chat")
+	}
+
+	// Forgetting a message takes the chat back under MinChatMessages
+	chats.UnfeedAt("work", now, "the deadline moved")
+	if _, ok := chats.Brain("work"); ok {
+		t.Error("used the brain of a chat after unfeeding it under MinChatMessages")
+	}
+
+	brainSettings.ChatBrains = false
+	disabled := NewChats(brainSettings)
+	disabled.FeedAt("work", now, "the deadline is tomorrow", "the deadline moved")
+	if _, ok := disabled.Brain("work"); ok {
+		t.Error("fed a chat brain with ChatBrains off")
+	}
+}
+
+func Test_Template_generator_fills_in_fed_messages(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.Generator = GeneratorTemplate
+	generator, err := NewGenerator(brainSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := generator.(Template); !ok {
+		t.Fatalf("created a %T, expected the template generator", generator)
+	}
+	template := NewTemplateWithSource(brainSettings, rand.NewSource(1))
+	animals := []string{"cat", "dog", "fox", "owl", "bat", "elk", "yak", "emu", "gnu", "ape",
+		"eel", "hen", "ram", "cow", "pig", "rat", "ant", "bee", "cod", "koi", "jay", "asp"}
+	for _, animal := range animals {
+		template.Feed("the " + animal + " is here")
+	}
+	for i := 0; i < 20; i++ {
+		// "the", "is" and "here" are in every message, so they're kept, the animal is replaced
+		words := strings.Fields(template.Generate())
+		if len(words) != 4 || words[0] != "the" || words[2] != "is" || words[3] != "here" {
+			t.Fatalf("generated %q, expected the shape of the fed messages", strings.Join(words, " "))
+		}
+	}
+	if generated, seed := template.GenerateSeeded(); template.Regenerate(seed) != generated {
+		t.Errorf("regenerated %q, expected %q", template.Regenerate(seed), generated)
+	}
+	if generated := template.GenerateAround("owl"); !strings.Contains(generated, "owl") {
+		t.Errorf("generated %q around owl", generated)
+	}
+	if stats := template.Stats(1); stats.Links != len(animals) || stats.TopWords[0].Count != len(animals) {
+		t.Errorf("stats %+v, expected a link per fed message", stats)
+	}
+
+	template.Reset()
+	if generated := template.Generate(); generated != "" {
+		t.Errorf("generated %q after a reset", generated)
+	}
+	brainSettings.Generator = "gpt"
+	if _, err := NewGenerator(brainSettings); errors.Cause(err) != ErrUnknownGenerator {
+		t.Errorf("created an unknown generator, error %v", err)
+	}
+}
+
+func Test_Chain_length_out_of_range_is_rejected(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	for _, length := range []int{0, gomarkov.MaxLinksLength + 1} {
+		brainSettings.ChainLength = length
+		if _, err := NewGenerator(brainSettings); errors.Cause(err) != ErrBadChainLength {
+			t.Errorf("created a generator with chain length %d, error %v", length, err)
+		}
+	}
+	// The template generator doesn't use it
+	brainSettings.Generator = GeneratorTemplate
+	if _, err := NewGenerator(brainSettings); err != nil {
+		t.Errorf("template generator rejected the chain length: %v", err)
+	}
+	brainSettings.Generator = GeneratorMarkov
+	brainSettings.ChainLength = gomarkov.MaxLinksLength
+	if _, err := NewGenerator(brainSettings); err != nil {
+		t.Errorf("rejected the longest chain length: %v", err)
+	}
+}
+
+func Test_Punctuation_is_kept_when_enabled(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.KeepPunctuation = true
+	// Long enough for the two quotes to be told apart
+	brainSettings.ChainLength = 3
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	brain.Feed(`Well, "that's" it (for now)!`)
+	if generated := brain.Generate(); generated != `Well, "that's" it (for now)!` {
+		t.Errorf("generated %q, expected the punctuation of the fed message", generated)
+	}
+	if name := brain.GenerateName(""); stringer.IsPunctuation(name) {
+		t.Errorf("made up %q out of punctuation", name)
+	}
+
+	// Without it, punctuation is split off and lost
+	brain = NewWithSource(settings.Default.Brain, rand.NewSource(1))
+	brain.Feed(`Well, "that's" it (for now)!`)
+	if generated := brain.Generate(); generated != `Well "that's" it (for now)` {
+		t.Errorf("generated %q, expected the punctuation in SplitChars to be dropped", generated)
+	}
+}
+
+func Test_Messages_are_normalized_before_feeding(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.Normalize = settings.Normalize{
+		URLs:               true,
+		URLReplacement:     "LINK",
+		Mentions:           true,
+		MentionReplacement: "",
+		Emoji:              true,
+		EmojiReplacement:   "",
+		Code:               true,
+		CodeReplacement:    "code",
+		Whitespace:         true,
+		Lowercase:          true,
+	}
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	brain.Feed("Hey @bob <@!1234>  look <:tusk:5678> at https://example.com/tusk?x=1 :joy: `rm -rf /` by 10:30:00")
+	expected := "hey look at link code by 10:30:00"
+	if generated := brain.Generate(); generated != expected {
+		t.Errorf("generated %q, expected %q", generated, expected)
+	}
+	// Topics are normalized the same way
+	if generated := brain.GenerateFrom("LOOK"); generated != "look at link code by 10:30:00" {
+		t.Errorf("generated %q from a capitalized topic", generated)
+	}
+	// And so are words
+	brain.Feed("Fish swim deep")
+	for i := 0; i < 20; i++ {
+		if generated := brain.GenerateAround("Code"); generated != expected {
+			t.Fatalf("generated %q around a capitalized word", generated)
+		}
+	}
+	if name := brain.GenerateName("LOO"); !strings.HasPrefix(name, "loo") {
+		t.Errorf("made up %q from a capitalized prefix", name)
+	}
+	var exported bytes.Buffer
+	if err := brain.Export(&exported, gomarkov.FormatJSON, "LOOK", 1); err != nil {
+		t.Errorf("exporting a capitalized word: %v", err)
+	} else if !strings.Contains(exported.String(), `"look"`) {
+		t.Errorf("exported %s around a capitalized word", exported.String())
+	}
+	if err := brain.Export(&exported, gomarkov.FormatJSON, "@bob", 1); err != gomarkov.ErrUnknownWord {
+		t.Errorf("exported a word with nothing left after normalizing, error %v", err)
+	}
+	if normalized := Normalize(brainSettings.Normalize, "mail me@example.com"); normalized != "mail me@example.com" {
+		t.Errorf("normalized an e-mail address into %q", normalized)
+	}
+	if !NeedsRebuild(settings.Default.Brain, brainSettings) {
+		t.Errorf("changing the normalization doesn't rebuild the brain")
+	}
+}
+
+func Test_Blocked_terms_are_never_generated(t *testing.T) {
+	filter, err := NewFilter(settings.Filter{
+		Words:    []string{"Secret"},
+		Patterns: []string{`\d{3}-\d{4}`},
+		Attempts: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for message, expected := range map[string]bool{
+		"the secret is out":   true,
+		"SECRET!":             true,
+		"secretly":            false,
+		"call me at 555-1234": true,
+		"call me maybe":       false,
+	} {
+		if _, blocked := filter.Blocked(message); blocked != expected {
+			t.Errorf("%q blocked: %v, expected %v", message, blocked, expected)
+		}
+	}
+	if err := filter.Check("a secret"); errors.Cause(err) != ErrBlocked {
+		t.Errorf("checking a blocked message returned %v", err)
+	}
+
+	brain := NewFiltered(NewWithSource(settings.Default.Brain, rand.NewSource(1)), filter)
+	brain.Feed("tell me a secret", "tell me a joke")
+	for i := 0; i < 20; i++ {
+		if generated := brain.Generate(); generated != "tell me a joke" {
+			t.Fatalf("generated %q, expected only the message without blocked terms", generated)
+		}
+	}
+	// Every message starting with a blocked word is blocked, so nothing is generated
+	if generated := brain.GenerateFrom("secret"); generated != "" {
+		t.Errorf("generated %q from a blocked topic", generated)
+	}
+
+	if err := filter.UpdateSettings(settings.Filter{Patterns: []string{"("}}); err == nil {
+		t.Errorf("updated the filter with a broken pattern")
+	}
+	if _, blocked := filter.Blocked("the secret is out"); !blocked {
+		t.Errorf("a broken pattern replaced the blocklist")
+	}
+}
+
+func Test_Snapshot_loads_the_same_brain(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.ChainLength = 2
+	brainSettings.RejectCopies = true
+	brainSettings.MaxOverlap = 0.5
+	brainSettings.HalfLifeDays = 1
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	now := time.Now().Unix()
+	brain.FeedAt(now-24*60*60, "the cat sat on the mat", "the dog sat on the log")
+	brain.FeedAt(now, "a cat ate the fish", "the dog ate the cat")
+	path := filepath.Join(t.TempDir(), "gotuskgo.brain")
+	if err := brain.SaveSnapshot(path, 42); err != nil {
+		t.Fatal(err)
+	}
+	loaded, lastID, err := LoadSnapshot(path, brainSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastID != 42 {
+		t.Errorf("loaded last message ID %d, expected 42", lastID)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		if generated, loadedGenerated := brain.Regenerate(seed), loaded.Regenerate(seed); generated != loadedGenerated {
+			t.Fatalf("seed %d generated %q, and %q after loading", seed, generated, loadedGenerated)
+		}
+	}
+	stats, loadedStats := brain.Stats(10), loaded.Stats(10)
+	// The entropy is summed up in map order, so it can be off by a rounding error
+	if math.Abs(stats.Entropy-loadedStats.Entropy) < 1e-9 {
+		loadedStats.Entropy = stats.Entropy
+	}
+	if !reflect.DeepEqual(stats, loadedStats) {
+		t.Errorf("stats %+v, and %+v after loading", stats, loadedStats)
+	}
+	if !reflect.DeepEqual(brain.sources, loaded.sources) {
+		t.Errorf("the sources of the quality filters changed after loading")
+	}
+	if *brain.decay != *loaded.decay {
+		t.Errorf("decay %+v, and %+v after loading", *brain.decay, *loaded.decay)
+	}
+	if generated := loaded.GenerateName("c"); !strings.HasPrefix(generated, "c") {
+		t.Errorf("made up %q from the loaded name chain", generated)
+	}
+
+	// A corrupt snapshot is an error, never a panic
+	snapshot, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(snapshot); i += 7 {
+		if err := ioutil.WriteFile(path, snapshot[:i], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := LoadSnapshot(path, brainSettings); err == nil {
+			t.Fatalf("loaded a snapshot cut at %d of %d bytes", i, len(snapshot))
+		}
+	}
+}
+
+func Test_Snapshot_of_other_settings_is_rebuilt(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	path := filepath.Join(t.TempDir(), "gotuskgo.brain")
+	brain := New(brainSettings)
+	brain.Feed("the cat sat on the mat")
+	if err := brain.SaveSnapshot(path, 1); err != nil {
+		t.Fatal(err)
+	}
+	for name, change := range map[string]func(s *settings.Brain){
+		"chain length": func(s *settings.Brain) { s.ChainLength = 2 },
+		"end tokens":   func(s *settings.Brain) { s.EndTokens = false },
+		"half-life":    func(s *settings.Brain) { s.HalfLifeDays = 1 },
+		"name length":  func(s *settings.Brain) { s.NameLength = 2 },
+		"filters":      func(s *settings.Brain) { s.RejectCopies = true },
+		"punctuation":  func(s *settings.Brain) { s.KeepPunctuation = true },
+		"split chars":  func(s *settings.Brain) { s.SplitChars = " " },
+		"normalize":    func(s *settings.Brain) { s.Normalize.Lowercase = true },
+		"generator":    func(s *settings.Brain) { s.Generator = GeneratorTemplate },
+	} {
+		changed := brainSettings
+		change(&changed)
+		if !NeedsRebuild(brainSettings, changed) {
+			t.Errorf("changing the %s doesn't rebuild the brain", name)
+		}
+		if _, _, err := LoadSnapshot(path, changed); err != ErrSnapshotMismatch {
+			t.Errorf("loading a snapshot with another %s returned %v", name, err)
+		}
+	}
+	// Settings that don't change how the brain is fed keep the snapshot
+	changed := brainSettings
+	changed.Temperature = 2
+	changed.MaxGeneratedLength = 5
+	if NeedsRebuild(brainSettings, changed) {
+		t.Errorf("changing how messages are generated rebuilds the brain")
+	}
+	if _, _, err := LoadSnapshot(path, changed); err != nil {
+		t.Errorf("loading a snapshot with other generation settings returned %v", err)
+	}
+}
+
+func Test_Topic_starts_the_generated_message(t *testing.T) {
+	brain := NewWithSource(settings.Default.Brain, rand.NewSource(1))
+	brain.Feed("the cat sat on the mat", "a dog ate the fish")
+	for i := 0; i < 20; i++ {
+		if generated := brain.GenerateFrom("the cat"); !strings.HasPrefix(generated, "the cat ") {
+			t.Fatalf("generated %q, expected it to start with the topic", generated)
+		}
+		if generated := brain.GenerateFrom("a"); !strings.HasPrefix(generated, "a dog ") {
+			t.Fatalf("generated %q, expected it to start with the topic", generated)
+		}
+	}
+	// Unknown topics are ignored
+	for _, topic := range []string{"zebra", "zebra crossing", ""} {
+		generated := brain.GenerateFrom(topic)
+		if generated == "" || strings.Contains(generated, "zebra") {
+			t.Errorf("generated %q from the unknown topic %q", generated, topic)
+		}
+	}
+}
+
+func Test_Word_is_generated_around(t *testing.T) {
+	brain := NewWithSource(settings.Default.Brain, rand.NewSource(1))
+	brain.Feed("one quick brown fox jumps high", "a red fox ran away")
+	for i := 0; i < 20; i++ {
+		generated := brain.GenerateAround("fox")
+		// The message goes backward to the start of a fed message, and forward to the end of one
+		if !strings.Contains(generated, " fox ") {
+			t.Fatalf("generated %q, expected the word in the middle of it", generated)
+		}
+		if !strings.HasPrefix(generated, "one quick brown ") && !strings.HasPrefix(generated, "a red ") {
+			t.Fatalf("generated %q, expected it to start like a fed message", generated)
+		}
+		if !strings.HasSuffix(generated, " jumps high") && !strings.HasSuffix(generated, " ran away") {
+			t.Fatalf("generated %q, expected it to end like a fed message", generated)
+		}
+	}
+	// An unknown word is left out
+	if generated := brain.GenerateAround("zebra"); generated == "" || strings.Contains(generated, "zebra") {
+		t.Errorf("generated %q around an unknown word", generated)
+	}
+}
+
+func Test_Unfed_messages_are_forgotten(t *testing.T) {
+	brainSettings := settings.Default.Brain
+	brainSettings.HalfLifeDays = 1
+	kept := NewWithSource(brainSettings, rand.NewSource(1))
+	kept.Feed("one quick brown fox")
+	brain := NewWithSource(brainSettings, rand.NewSource(1))
+	brain.Feed("one quick brown fox")
+	// Unfeeding it at the time it was fed removes it with the weight it was fed with
+	old := time.Now().Add(-48 * time.Hour).Unix()
+	brain.FeedAt(old, "red apples grow on trees", "one quick cat")
+	brain.UnfeedAt(old, "red apples grow on trees", "one quick cat")
+
+	if stats, keptStats := brain.Stats(0), kept.Stats(0); stats.Links != keptStats.Links ||
+		stats.Vocabulary != keptStats.Vocabulary || stats.Transitions != keptStats.Transitions {
+		t.Errorf("stats %+v after unfeeding, expected the stats %+v of the kept message", stats, keptStats)
+	}
+	for i := 0; i < 20; i++ {
+		if generated := brain.Generate(); generated != "one quick brown fox" {
+			t.Fatalf("generated %q, expected only the kept message", generated)
+		}
+	}
+	if name := brain.GenerateName("ap"); name != "" {
+		t.Errorf("made up %q out of a word that was unfed", name)
+	}
+}
+
+func Test_Sampling_can_be_changed_per_call(t *testing.T) {
+	brain := NewWithSource(settings.Default.Brain, rand.NewSource(1))
+	brain.Feed("the cat sat", "the cat sat", "the cat sat", "the dog ran")
+	greedy := WithSampling(brain, gomarkov.Sampling{TopK: 1})
+	for i := 0; i < 20; i++ {
+		if generated := greedy.Generate(); generated != "the cat sat" {
+			t.Fatalf("generated %q, expected only the most common words", generated)
+		}
+	}
+	// The brain itself keeps the sampling of its settings
+	dog := false
+	for i := 0; i < 100 && !dog; i++ {
+		dog = brain.Generate() == "the dog ran"
+	}
+	if !dog {
+		t.Errorf("the brain only generated the most common words after sampling a view of it")
+	}
+
+	template := NewTemplate(settings.Default.Brain)
+	if sampled := WithSampling(template, gomarkov.Sampling{TopK: 1}); sampled != Generator(template) {
+		t.Errorf("sampling changed the template generator")
+	}
+}
+
+func Test_Feeding_counts_the_new_links(t *testing.T) {
+	for _, generator := range []Generator{New(settings.Default.Brain), NewTemplate(settings.Default.Brain)} {
+		before := generator.Stats(0).Links
+		now := time.Now().Unix()
+		if links := generator.FeedAt(now, "the cat sat", "the dog sat"); links != generator.Stats(0).Links-before {
+			t.Errorf("%T counted %d new links, expected %d", generator, links, generator.Stats(0).Links-before)
+		}
+		// Nothing is new the second time
+		if links := generator.FeedAt(now, "the cat sat"); links != 0 {
+			t.Errorf("%T counted %d new links feeding a message again", generator, links)
+		}
+	}
+}
+
+func Test_Messages_are_fed_from_a_reader(t *testing.T) {
+	brain := NewWithSource(settings.Default.Brain, rand.NewSource(1))
+	skipSecret := func(message string) bool { return strings.Contains(message, "secret") }
+	fed, err := brain.FeedFrom(strings.NewReader("the cat sat\r\n\nthe secret plan\nthe cat sat"), "", skipSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fed != 2 {
+		t.Errorf("fed %d messages, expected the 2 that weren't skipped", fed)
+	}
+	for i := 0; i < 20; i++ {
+		// Every message is its own, and the skipped one is left out
+		if generated := brain.Generate(); generated != "the cat sat" {
+			t.Fatalf("generated %q, expected only the fed message", generated)
+		}
+	}
+}
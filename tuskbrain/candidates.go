@@ -0,0 +1,58 @@
+package tuskbrain
+
+// candidate is a generated message being considered by pickBest
+type candidate struct {
+	text       string
+	seed       int64
+	perplexity float64
+	passes     bool
+}
+
+// pickBest calls generate as many times as the Candidates setting says, and returns the generated message
+// (and its seed) that passes the quality filters with the lowest perplexity. If none pass, the one with
+// the lowest perplexity is returned anyway. Empty messages are never picked, unless all of them are empty.
+// The picked message is detokenized, see detokenizeText.
+func (b Brain) pickBest(generate func() (string, int64)) (string, int64) {
+	if b.config.Candidates <= 1 {
+		text, seed := generate()
+		return detokenizeText(b.config, text), seed
+	}
+	var best *candidate
+	for i := 0; i < b.config.Candidates; i++ {
+		text, seed := generate()
+		if text == "" {
+			continue
+		}
+		current := b.judge(text, seed)
+		if best == nil || current.better(*best) {
+			best = &current
+		}
+	}
+	if best == nil {
+		return "", 0
+	}
+	return detokenizeText(b.config, best.text), best.seed
+}
+
+// judge scores the given generated message and checks it against the quality filters
+func (b Brain) judge(text string, seed int64) candidate {
+	words := tokenize(b.config, text)
+	passes := len(words) >= b.config.MinWords &&
+		(b.config.MaxWords == 0 || len(words) <= b.config.MaxWords) &&
+		!b.sources.isCopy(words) &&
+		(b.config.MaxOverlap <= 0 || b.sources.overlap(words) <= b.config.MaxOverlap)
+	return candidate{
+		text:       text,
+		seed:       seed,
+		perplexity: b.chain.Score(words).Perplexity,
+		passes:     passes,
+	}
+}
+
+// better reports whether the candidate should be picked over the other one
+func (c candidate) better(other candidate) bool {
+	if c.passes != other.passes {
+		return c.passes
+	}
+	return c.perplexity < other.perplexity
+}
@@ -0,0 +1,112 @@
+package tuskbrain
+
+import (
+	"sync"
+
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// Chats keeps a brain for every chat, fed only the messages received in that chat, so that messages
+// generated for a chat sound like that chat instead of every chat the bot is in. A chat can be identified
+// by any string, an empty one being no chat at all. It is safe for concurrent use.
+type Chats struct {
+	// lock guards everything below, the brains of the chats have their own locks
+	lock   *sync.RWMutex
+	config *settings.Brain
+	chats  map[string]*chat
+}
+
+// chat is the brain of a single chat, along with the amount of messages fed to it
+type chat struct {
+	brain    Generator
+	messages int
+}
+
+// NewChats creates an empty set of chat brains. With the ChatBrains setting off, nothing is fed to them.
+func NewChats(brainSettings settings.Brain) Chats {
+	return Chats{
+		lock:   &sync.RWMutex{},
+		config: &brainSettings,
+		chats:  make(map[string]*chat),
+	}
+}
+
+// Enabled reports whether the chat brains are fed, as set by the ChatBrains setting
+func (c Chats) Enabled() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.config.ChatBrains
+}
+
+// UpdateSettings replaces the brain settings of every chat brain. Like with Brain.UpdateSettings,
+// settings that change how the brains are fed need the chat brains to be rebuilt.
+func (c Chats) UpdateSettings(brainSettings settings.Brain) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	*c.config = brainSettings
+	for _, ch := range c.chats {
+		ch.brain.UpdateSettings(brainSettings)
+	}
+}
+
+// FeedAt feeds the given messages, received in the given chat at the given Unix time, to the brain of that chat
+func (c Chats) FeedAt(chatID string, unix int64, messages ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if chatID == "" || !c.config.ChatBrains {
+		return
+	}
+	ch := c.chats[chatID]
+	if ch == nil {
+		ch = &chat{brain: newGenerator(*c.config)}
+		c.chats[chatID] = ch
+	}
+	ch.brain.FeedAt(unix, messages...)
+	ch.messages += len(messages)
+}
+
+// UnfeedAt removes the given messages, received in the given chat at the given Unix time, from the brain
+// of that chat. A chat with no messages left is forgotten.
+func (c Chats) UnfeedAt(chatID string, unix int64, messages ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch := c.chats[chatID]
+	if ch == nil {
+		return
+	}
+	ch.brain.UnfeedAt(unix, messages...)
+	ch.messages -= len(messages)
+	if ch.messages <= 0 {
+		delete(c.chats, chatID)
+	}
+}
+
+// Brain returns the brain of the given chat, if it was fed at least as many messages as
+// the MinChatMessages setting says. Otherwise, the brain fed every message should be used.
+func (c Chats) Brain(chatID string) (Generator, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ch := c.chats[chatID]
+	if ch == nil || ch.messages < c.config.MinChatMessages {
+		return nil, false
+	}
+	return ch.brain, true
+}
+
+// Prune prunes the brain of every chat that can be pruned, see Brain.Prune. Returns the amount of
+// transitions and links removed from all of them.
+func (c Chats) Prune() (int, int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	transitions, links := 0, 0
+	for _, ch := range c.chats {
+		pruner, ok := ch.brain.(Pruner)
+		if !ok {
+			continue
+		}
+		chatTransitions, chatLinks := pruner.Prune()
+		transitions += chatTransitions
+		links += chatLinks
+	}
+	return transitions, links
+}
@@ -27,7 +27,11 @@ func New(db *gorm.DB) Wrapper {
 
 // AutoMigrate runs the AutoMigrate GORM tool
 func (w Wrapper) AutoMigrate() error {
-	return w.db.AutoMigrate(&General{}, &Message{}, &Subscription{}, &SubscribeError{}).Error
+	if err := w.db.AutoMigrate(&General{}, &Message{}, &Subscription{}, &SubscribeError{}).Error; err != nil {
+		return err
+	}
+	// Messages from before the time was recorded are treated as received now
+	return w.db.Model(&Message{}).Where("unix = 0").Update("unix", time.Now().Unix()).Error
 }
 
 // GetOffset gets the current offset
@@ -49,20 +53,60 @@ func (w Wrapper) SetOffset(value int) error {
 	return w.db.Save(&offset).Error
 }
 
-// AddMessage adds a given message to the messages list in the database
-func (w Wrapper) AddMessage(msg string) error {
+// AddMessage adds a given message, received in the given chat at the given Unix time, to the messages list in the database
+func (w Wrapper) AddMessage(msg string, unix int64, chat string) error {
 	message := Message{
 		Content: msg,
+		Unix:    unix,
+		Chat:    chat,
 	}
 	return w.db.Save(&message).Error
 }
 
+// DeleteMessages deletes every message with the given content, returning the deleted messages
+func (w Wrapper) DeleteMessages(content string) ([]Message, error) {
+	msg := []Message{}
+	if err := w.db.Where("content = ?", content).Find(&msg).Error; err != nil {
+		return nil, err
+	}
+	if len(msg) == 0 {
+		return msg, nil
+	}
+	ids := make([]int, len(msg))
+	for i, message := range msg {
+		ids[i] = message.ID
+	}
+	return msg, w.db.Where("id in (?)", ids).Delete(&Message{}).Error
+}
+
 // GetAllMessages returns all messages
 func (w Wrapper) GetAllMessages() ([]Message, error) {
 	msg := []Message{}
 	return msg, w.db.Find(&msg).Error
 }
 
+// GetChatMessages returns all messages that were received in a chat
+func (w Wrapper) GetChatMessages() ([]Message, error) {
+	msg := []Message{}
+	return msg, w.db.Where("chat != ''").Find(&msg).Error
+}
+
+// GetMessagesAfter returns all messages with an ID greater than the given one
+func (w Wrapper) GetMessagesAfter(id int) ([]Message, error) {
+	msg := []Message{}
+	return msg, w.db.Where("id > ?", id).Find(&msg).Error
+}
+
+// GetLastMessageID returns the ID of the newest message, or 0 if there are no messages
+func (w Wrapper) GetLastMessageID() (int, error) {
+	msg := Message{}
+	err := w.db.Order("id desc").First(&msg).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return msg.ID, err
+}
+
 // GetSubscription returns a subscription, if found
 func (w Wrapper) GetSubscription(chatID int64) (Subscription, error) {
 	sub := Subscription{}
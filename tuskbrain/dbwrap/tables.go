@@ -10,6 +10,10 @@ type General struct {
 type Message struct {
 	ID      int    `gorm:"primary_key"`
 	Content string `gorm:"not null"`
+	// Unix is when the message was received
+	Unix int64 `gorm:"not null;default:0"`
+	// Chat is the chat the message was received in, empty if it wasn't received in a chat
+	Chat string `gorm:"not null;default:'';index"`
 }
 
 // Subscription contains a subscibed chat ID
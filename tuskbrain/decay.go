@@ -0,0 +1,50 @@
+package tuskbrain
+
+import (
+	"math"
+
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// maxDecayWeight is the largest weight a message is fed with before the brain is aged,
+// keeping the weights of new messages from growing out of the range of a float64
+const maxDecayWeight = 1 << 20
+
+// decay weighs messages by how recently they were received, halving the weight of a message
+// every half-life. Instead of lowering the weight of every message as time passes, newer messages
+// get higher weights, which gives the same proportions.
+type decay struct {
+	// halfLife is the half-life in seconds
+	halfLife float64
+	// epoch is the Unix time at which a received message weighs 1
+	epoch int64
+}
+
+// newDecay creates the decay for the given settings, nil if decay is disabled
+func newDecay(brainSettings settings.Brain, now int64) *decay {
+	if brainSettings.HalfLifeDays <= 0 {
+		return nil
+	}
+	return &decay{
+		halfLife: brainSettings.HalfLifeDays * 24 * 60 * 60,
+		epoch:    now,
+	}
+}
+
+// weight returns the weight of a message received at the given Unix time, 1 if decay is disabled.
+// Messages too old for their weight to fit in a float64 get the smallest one, so that they're still fed.
+func (d *decay) weight(unix int64) float64 {
+	if d == nil {
+		return 1
+	}
+	return math.Max(math.Exp2(float64(unix-d.epoch)/d.halfLife), math.SmallestNonzeroFloat64)
+}
+
+// clone returns a copy of the decay
+func (d *decay) clone() *decay {
+	if d == nil {
+		return nil
+	}
+	clone := *d
+	return &clone
+}
@@ -0,0 +1,171 @@
+package tuskbrain
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// ErrBlocked is returned when a message contains a term of the blocklist
+var ErrBlocked = errors.New("Message contains a blocked term")
+
+// Filter finds the terms of a blocklist in messages, so that the bot neither learns nor says them.
+// It is safe for concurrent use, and copies of it share the blocklist.
+type Filter struct {
+	// lock guards the blocklist
+	lock      *sync.RWMutex
+	blocklist *blocklist
+}
+
+// blocklist is the filter settings, along with their words and patterns compiled in the same order
+type blocklist struct {
+	config settings.Filter
+	terms  []blockedTerm
+}
+
+// blockedTerm is a compiled word or pattern of the blocklist
+type blockedTerm struct {
+	term    string
+	pattern *regexp.Regexp
+}
+
+// NewFilter creates a filter blocking the terms of the given blocklist.
+// Returns an error if any of its patterns isn't a valid regular expression.
+func NewFilter(filterSettings settings.Filter) (Filter, error) {
+	f := Filter{
+		lock:      &sync.RWMutex{},
+		blocklist: &blocklist{},
+	}
+	return f, f.UpdateSettings(filterSettings)
+}
+
+// UpdateSettings replaces the blocklist. If any of its patterns isn't a valid regular expression,
+// an error is returned and the old blocklist is kept.
+func (f Filter) UpdateSettings(filterSettings settings.Filter) error {
+	terms, err := compileBlocklist(filterSettings)
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	*f.blocklist = blocklist{config: filterSettings, terms: terms}
+	return nil
+}
+
+// Blocked returns the first word or pattern of the blocklist found in the given message,
+// and whether there was one at all
+func (f Filter) Blocked(message string) (string, bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	for _, t := range f.blocklist.terms {
+		if t.pattern.MatchString(message) {
+			return t.term, true
+		}
+	}
+	return "", false
+}
+
+// Check returns ErrBlocked, along with the blocked term, if the given message contains one
+func (f Filter) Check(message string) error {
+	if term, blocked := f.Blocked(message); blocked {
+		return errors.Wrap(ErrBlocked, term)
+	}
+	return nil
+}
+
+// attempts returns how many times a message should be generated before giving up on it
+func (f Filter) attempts() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if f.blocklist.config.Attempts < 1 {
+		return 1
+	}
+	return f.blocklist.config.Attempts
+}
+
+// compileBlocklist compiles the words and patterns of the given blocklist.
+// Words only match between characters that aren't letters, digits or underscores, and empty terms,
+// which would block every message, are skipped.
+func compileBlocklist(filterSettings settings.Filter) ([]blockedTerm, error) {
+	terms := make([]blockedTerm, 0, len(filterSettings.Words)+len(filterSettings.Patterns))
+	for _, word := range filterSettings.Words {
+		if word == "" {
+			continue
+		}
+		terms = append(terms, blockedTerm{
+			term:    word,
+			pattern: regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])` + regexp.QuoteMeta(word) + `(?:[^\pL\pN_]|$)`),
+		})
+	}
+	for _, pattern := range filterSettings.Patterns {
+		if pattern == "" {
+			continue
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "regexp.Compile %q", pattern)
+		}
+		terms = append(terms, blockedTerm{term: pattern, pattern: compiled})
+	}
+	return terms, nil
+}
+
+// Filtered is a Generator which never makes up a message containing a blocked term. Blocked messages are
+// generated again, as many times as the Attempts setting of the filter says, after which an empty
+// message is made up instead.
+type Filtered struct {
+	Generator
+	filter Filter
+}
+
+// NewFiltered creates a Generator which makes up messages with the given generator, blocking the terms
+// of the given filter. Everything other than generating is left to the given generator.
+func NewFiltered(generator Generator, filter Filter) Filtered {
+	return Filtered{Generator: generator, filter: filter}
+}
+
+// Generate makes up a new message without blocked terms
+func (f Filtered) Generate() string {
+	return f.generate(f.Generator.Generate)
+}
+
+// GenerateSeeded makes up a new message without blocked terms, along with the seed it was generated with.
+// The seed is 0 when every attempt was blocked.
+func (f Filtered) GenerateSeeded() (string, int64) {
+	for i := f.filter.attempts(); i > 0; i-- {
+		generated, seed := f.Generator.GenerateSeeded()
+		if _, blocked := f.filter.Blocked(generated); !blocked {
+			return generated, seed
+		}
+	}
+	return "", 0
+}
+
+// GenerateFrom makes up a new message without blocked terms, starting with the given topic
+func (f Filtered) GenerateFrom(topic string) string {
+	return f.generate(func() string { return f.Generator.GenerateFrom(topic) })
+}
+
+// GenerateAround makes up a new message without blocked terms, containing the given word
+func (f Filtered) GenerateAround(word string) string {
+	return f.generate(func() string { return f.Generator.GenerateAround(word) })
+}
+
+// GenerateName makes up a new word that isn't blocked, starting with the given prefix
+func (f Filtered) GenerateName(prefix string) string {
+	return f.generate(func() string { return f.Generator.GenerateName(prefix) })
+}
+
+// generate calls generate until it returns a message without blocked terms, giving up with
+// an empty message after the attempts of the filter
+func (f Filtered) generate(generate func() string) string {
+	for i := f.filter.attempts(); i > 0; i-- {
+		generated := generate()
+		if _, blocked := f.filter.Blocked(generated); !blocked {
+			return generated
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package tuskbrain
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+const (
+	// GeneratorMarkov is the name of the Markov chain generator, Brain, which is used when no generator is set
+	GeneratorMarkov = "markov"
+	// GeneratorTemplate is the name of the template generator, Template
+	GeneratorTemplate = "template"
+)
+
+var (
+	// ErrUnknownGenerator is returned when the brain settings name a generator that doesn't exist
+	ErrUnknownGenerator = errors.New("Unknown generator")
+	// ErrBadChainLength is returned when the chain length of the brain settings is out of the range
+	// the markov chain supports
+	ErrBadChainLength = errors.New("Chain length out of range")
+	// ErrUnsupported is returned when asking a generator for something it can't do
+	ErrUnsupported = errors.New("Not supported by the generator")
+)
+
+// Generator makes up messages from the messages fed to it. Implementations are safe for concurrent use.
+type Generator interface {
+	// Feed feeds the given messages, received just now
+	Feed(messages ...string)
+	// FeedAt feeds the given messages, received at the given Unix time, returning the amount of links
+	// they added, as counted by Stats
+	FeedAt(unix int64, messages ...string) int
+	// UnfeedAt removes the given messages, received at the given Unix time, as if they were never fed
+	UnfeedAt(unix int64, messages ...string)
+	// Reset forgets everything that was fed, keeping the settings
+	Reset()
+	// UpdateSettings replaces the brain settings, the ones that change how messages are fed
+	// only apply once the generator is fed every message again, see NeedsRebuild
+	UpdateSettings(brainSettings settings.Brain)
+
+	// Generate makes up a new message
+	Generate() string
+	// GenerateSeeded makes up a new message, along with the seed it was generated with, which is
+	// logged so that the message can be looked into
+	GenerateSeeded() (string, int64)
+	// Regenerate makes up the message GenerateSeeded made up with the given seed again, which only works
+	// as long as nothing was fed or removed since
+	Regenerate(seed int64) string
+	// GenerateFrom makes up a new message starting with the given topic, or any message if the topic
+	// is empty or unknown
+	GenerateFrom(topic string) string
+	// GenerateAround makes up a new message containing the given word, or any message if the word is unknown
+	GenerateAround(word string) string
+	// GenerateName makes up a new word starting with the given prefix, or returns an empty string if it can't
+	GenerateName(prefix string) string
+
+	// Stats returns the statistics of the generator, with the top most frequent words
+	Stats(top int) gomarkov.Stats
+}
+
+// Exporter is a Generator that can write out what it was fed, see Brain.Export
+type Exporter interface {
+	Export(w io.Writer, format gomarkov.ExportFormat, word string, depth int) error
+}
+
+// Pruner is a Generator that can forget what it was rarely fed, see Brain.Prune
+type Pruner interface {
+	Prune() (int, int)
+}
+
+// Merger is a Generator that another brain can be blended into, see Brain.Merge
+type Merger interface {
+	Merge(other Brain, weight float64) error
+	Diff(other Brain) (gomarkov.Diff, error)
+}
+
+// Snapshotter is a Generator that can be saved to a snapshot, see Brain.SaveSnapshot
+type Snapshotter interface {
+	SaveSnapshot(path string, lastMessageID int) error
+}
+
+// WithSampling returns the given generator generating with the given sampling instead of the one in the
+// settings, see Brain.WithSampling. Generators that don't sample from a markov chain are returned as they are.
+func WithSampling(generator Generator, sampling gomarkov.Sampling) Generator {
+	if brain, ok := generator.(Brain); ok {
+		return brain.WithSampling(sampling)
+	}
+	return generator
+}
+
+// NewGenerator creates the generator named by the Generator setting
+func NewGenerator(brainSettings settings.Brain) (Generator, error) {
+	if err := CheckSettings(brainSettings); err != nil {
+		return nil, err
+	}
+	if brainSettings.Generator == GeneratorTemplate {
+		return NewTemplate(brainSettings), nil
+	}
+	return New(brainSettings), nil
+}
+
+// CheckSettings returns an error if no generator can be created with the given brain settings,
+// because the generator doesn't exist or the chain length is out of range
+func CheckSettings(brainSettings settings.Brain) error {
+	switch brainSettings.Generator {
+	case "", GeneratorMarkov:
+		if brainSettings.ChainLength < 1 || brainSettings.ChainLength > gomarkov.MaxLinksLength {
+			return errors.Wrapf(ErrBadChainLength, "%d is not between 1 and %d", brainSettings.ChainLength, gomarkov.MaxLinksLength)
+		}
+		return nil
+	case GeneratorTemplate:
+		return nil
+	}
+	return errors.Wrap(ErrUnknownGenerator, brainSettings.Generator)
+}
+
+// newGenerator creates the generator named by the Generator setting, the Markov chain
+// generator if there is no such generator
+func newGenerator(brainSettings settings.Brain) Generator {
+	generator, err := NewGenerator(brainSettings)
+	if err != nil {
+		return New(brainSettings)
+	}
+	return generator
+}
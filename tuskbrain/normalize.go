@@ -0,0 +1,82 @@
+package tuskbrain
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+var (
+	// codePattern matches code blocks, fenced by three backticks, and inline code in single backticks
+	codePattern = regexp.MustCompile("```[\\s\\S]*?```|`[^`\\n]+`")
+	// urlPattern matches links starting with a scheme or with www.
+	urlPattern = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.\-]*://|www\.)\S+`)
+	// emojiPattern matches Discord custom emoji, animated ones included, and :name: emoji codes.
+	// Names need a letter in them, so that times like 10:30:00 aren't emoji.
+	emojiPattern = regexp.MustCompile(`<a?:\w+:\d+>|:[\w+\-]*[[:alpha:]][\w+\-]*:`)
+	// mentionPattern matches Discord user, role and channel mentions, and @name mentions that aren't
+	// part of a word, so that e-mail addresses are left alone
+	mentionPattern = regexp.MustCompile(`<(?:@[!&]?|#)\d+>|\B@\w+`)
+)
+
+// normalizeStep is a single step of the normalization pipeline
+type normalizeStep struct {
+	enabled     func(n settings.Normalize) bool
+	pattern     *regexp.Regexp
+	replacement func(n settings.Normalize) string
+}
+
+// normalizeSteps are the replacing steps of the normalization pipeline, in the order they're applied.
+// Code goes first, as anything inside of it is code, and emoji go before mentions, as Discord
+// custom emoji look a lot like mentions.
+var normalizeSteps = []normalizeStep{
+	{
+		enabled:     func(n settings.Normalize) bool { return n.Code },
+		pattern:     codePattern,
+		replacement: func(n settings.Normalize) string { return n.CodeReplacement },
+	},
+	{
+		enabled:     func(n settings.Normalize) bool { return n.URLs },
+		pattern:     urlPattern,
+		replacement: func(n settings.Normalize) string { return n.URLReplacement },
+	},
+	{
+		enabled:     func(n settings.Normalize) bool { return n.Emoji },
+		pattern:     emojiPattern,
+		replacement: func(n settings.Normalize) string { return n.EmojiReplacement },
+	},
+	{
+		enabled:     func(n settings.Normalize) bool { return n.Mentions },
+		pattern:     mentionPattern,
+		replacement: func(n settings.Normalize) string { return n.MentionReplacement },
+	},
+}
+
+// Normalize changes the given message the way the Normalize settings say, before it's fed to the brain.
+// Code blocks, links, emoji codes and mentions are replaced first, then the message is lowercased and its
+// whitespace collapsed.
+func Normalize(normalize settings.Normalize, message string) string {
+	for _, step := range normalizeSteps {
+		if step.enabled(normalize) {
+			message = step.pattern.ReplaceAllLiteralString(message, step.replacement(normalize))
+		}
+	}
+	if normalize.Lowercase {
+		message = strings.ToLower(message)
+	}
+	if normalize.Whitespace {
+		message = strings.Join(strings.Fields(message), " ")
+	}
+	return message
+}
+
+// normalizeHash returns a hash of the Normalize settings, which is stored in snapshots
+// so that a snapshot of messages normalized differently isn't loaded
+func normalizeHash(normalize settings.Normalize) uint64 {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%#v", normalize)
+	return hash.Sum64()
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -16,9 +18,15 @@ const Path = "opdata/settings.json"
 // Default is the default application settings
 var Default = Application{
 	Brain: Brain{
+		Generator:          "markov",
 		SplitChars:         "-.,?!/\\\r \n\t",
 		MaxGeneratedLength: 30,
 		ChainLength:        1,
+		EndTokens:          true,
+		Candidates:         1,
+		OverlapLength:      3,
+		NameLength:         3,
+		MinChatMessages:    500,
 	},
 	GRPC: GRPC{
 		AuthCode: "changeme",
@@ -29,7 +37,8 @@ var Default = Application{
 		Discord:  "",
 	},
 	Database: Database{
-		Path: "opdata/gotuskgo.db",
+		Path:            "opdata/gotuskgo.db",
+		SnapshotMinutes: 30,
 	},
 	Messaging: Messaging{
 		NormalMinMinutes: 15,
@@ -37,6 +46,11 @@ var Default = Application{
 		SleepMinMinutes:  180,
 		SleepMaxMinutes:  200,
 	},
+	Filter: Filter{
+		Words:    []string{},
+		Patterns: []string{},
+		Attempts: 10,
+	},
 }
 
 // Application contains all the setting categories
@@ -46,13 +60,101 @@ type Application struct {
 	APIs      APIs      `json:"api_keys"`
 	Database  Database  `json:"database"`
 	Messaging Messaging `json:"messaging"`
+	Filter    Filter    `json:"filter"`
 }
 
 // Brain contains the settings for the markov brain
 type Brain struct {
-	SplitChars         string `json:"split_chars"`
-	MaxGeneratedLength int    `json:"max_generated_length"`
-	ChainLength        int    `json:"chain_length"`
+	// Generator is the name of the generator making up the messages, "markov" (or empty) for the markov chain,
+	// or "template" for filling in the fed messages with other fed words. Changing it rebuilds the brain.
+	// The template generator ignores the settings of the markov chain, such as the chain length.
+	Generator string `json:"generator"`
+	// SplitChars are the characters messages are split into words on. Changing it rebuilds the brain.
+	SplitChars string `json:"split_chars"`
+	// KeepPunctuation keeps punctuation as words of its own instead of splitting on SplitChars, which is
+	// ignored, and puts it back with the right spacing in generated messages. Changing it rebuilds the brain.
+	KeepPunctuation    bool `json:"keep_punctuation"`
+	MaxGeneratedLength int  `json:"max_generated_length"`
+	// ChainLength is the amount of words the markov chain looks at to pick the next one, from 1 to 6.
+	// Changing it rebuilds the brain.
+	ChainLength int `json:"chain_length"`
+	// EndTokens makes the brain remember where messages end, so that generated messages
+	// end naturally instead of running on. Changing it rebuilds the brain.
+	EndTokens bool `json:"end_tokens"`
+	// Backoff makes the brain fall back to shorter chains when the current chain
+	// has nowhere to go, so that longer chain lengths don't cut messages short
+	Backoff bool `json:"backoff"`
+	// Candidates is the amount of messages generated every time, of which the best one is picked.
+	// 0 or 1 uses the first generated message.
+	Candidates int `json:"candidates"`
+	// MinWords is the least amount of words a picked message can have
+	MinWords int `json:"min_words"`
+	// MaxWords is the most words a picked message can have, 0 for no limit
+	MaxWords int `json:"max_words"`
+	// RejectCopies rejects messages that are exactly the same as a fed message. Changing it rebuilds the brain.
+	RejectCopies bool `json:"reject_copies"`
+	// MaxOverlap is the largest share, from 0 to 1, of a message's OverlapLength word long phrases that can
+	// come from a single fed message, 0 to allow any. Changing either rebuilds the brain.
+	MaxOverlap    float64 `json:"max_overlap"`
+	OverlapLength int     `json:"overlap_length"`
+	// Temperature, TopK and TopP change how the next word is picked. A temperature under 1 makes
+	// messages more coherent, over 1 more chaotic, TopK and TopP only pick from the most common words.
+	// 0 leaves each of them off, picking words in proportion to how often they were fed.
+	Temperature float64 `json:"temperature"`
+	TopK        int     `json:"top_k"`
+	TopP        float64 `json:"top_p"`
+	// HalfLifeDays makes messages weigh half as much after every this many days, so that new messages
+	// weigh more than old ones. 0 to weigh every message the same. Changing it rebuilds the brain.
+	HalfLifeDays float64 `json:"half_life_days"`
+	// NameLength is the amount of characters the name chain looks at to pick the next character of a made up
+	// word, 0 to not keep a name chain. Changing it rebuilds the brain.
+	NameLength int `json:"name_length"`
+	// PruneBelow removes transitions fed fewer times than it, 0 to keep every transition.
+	// With a half-life set, a transition fed once at the time of the pruning weighs 1.
+	PruneBelow float64 `json:"prune_below"`
+	// MaxLinks is the most links the brain keeps, the least used ones are removed first. 0 for no limit.
+	MaxLinks int `json:"max_links"`
+	// PruneMinutes is the amount of minutes between prunings of the brain, 0 to never prune.
+	// Pruned transitions are still in the database, rebuilding the brain brings them back until the next pruning.
+	PruneMinutes int `json:"prune_minutes"`
+	// ChatBrains keeps a brain for every chat, fed only the messages received in it, for generating messages
+	// for that chat. MinChatMessages is the amount of messages a chat needs before its brain is used instead
+	// of the one fed every message. Chat brains are not snapshotted, they're rebuilt on every start.
+	ChatBrains      bool `json:"chat_brains"`
+	MinChatMessages int  `json:"min_chat_messages"`
+	// Normalize is what's changed in messages before they're fed. The database keeps the messages as they
+	// were received, changing it rebuilds the brain.
+	Normalize Normalize `json:"normalize"`
+}
+
+// Normalize contains the changes made to messages before they're fed to the brain
+type Normalize struct {
+	// URLs, Mentions, Emoji and Code replace links, mentions (@name, and <@id> on Discord), emoji codes
+	// (:name:, and <:name:id> on Discord) and code blocks with the matching replacement, empty to remove them
+	URLs               bool   `json:"urls"`
+	URLReplacement     string `json:"url_replacement"`
+	Mentions           bool   `json:"mentions"`
+	MentionReplacement string `json:"mention_replacement"`
+	Emoji              bool   `json:"emoji"`
+	EmojiReplacement   string `json:"emoji_replacement"`
+	Code               bool   `json:"code"`
+	CodeReplacement    string `json:"code_replacement"`
+	// Whitespace collapses every run of whitespace into a single space
+	Whitespace bool `json:"whitespace"`
+	// Lowercase makes messages lowercase, so that words are the same no matter how they're capitalized
+	Lowercase bool `json:"lowercase"`
+}
+
+// Filter contains the blocklist of terms that received messages are never stored with,
+// and that generated messages are never sent with
+type Filter struct {
+	// Words are blocked as whole words, no matter how they're capitalized
+	Words []string `json:"words"`
+	// Patterns are regular expressions, blocked wherever they match in a message
+	Patterns []string `json:"patterns"`
+	// Attempts is the amount of times a message is generated before giving up on one without blocked terms.
+	// 0 or 1 gives up on the first blocked message.
+	Attempts int `json:"attempts"`
 }
 
 // GRPC contains the GRPC settings
@@ -76,6 +178,24 @@ type APIs struct {
 // Database contains the settings for the SQLite database
 type Database struct {
 	Path string `json:"path"`
+	// SnapshotMinutes is the amount of minutes between brain snapshots, the default when it's 0,
+	// or negative to only snapshot when the brain is rebuilt
+	SnapshotMinutes int `json:"snapshot_minutes"`
+}
+
+// GetSnapshotMinutes returns the amount of minutes between brain snapshots, using the default
+// for settings written before snapshots existed
+func (d Database) GetSnapshotMinutes() int {
+	if d.SnapshotMinutes == 0 {
+		return Default.Database.SnapshotMinutes
+	}
+	return d.SnapshotMinutes
+}
+
+// GetSnapshotPath returns the path of the brain snapshot, which is stored
+// alongside the database with a .brain extension
+func (d Database) GetSnapshotPath() string {
+	return strings.TrimSuffix(d.Path, filepath.Ext(d.Path)) + ".brain"
 }
 
 // Messaging contains the settings related to messaging (e.g. min-max minutes between sendouts)
@@ -121,6 +241,10 @@ func Load() (Application, error) {
 	if sett.APIs == (APIs{}) {
 		sett.APIs = Default.APIs
 	}
+	// The filter has lists, so it can't be compared
+	if len(sett.Filter.Words) == 0 && len(sett.Filter.Patterns) == 0 && sett.Filter.Attempts == 0 {
+		sett.Filter = Default.Filter
+	}
 
 	return sett, nil
 }
@@ -0,0 +1,234 @@
+package tuskbrain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// snapshotMagic is written at the start of every brain snapshot file
+const snapshotMagic = "TUSK"
+
+// maxSnapshotString is the longest string the header of a brain snapshot can hold,
+// a longer one means the snapshot is corrupt
+const maxSnapshotString = 1 << 16
+
+// snapshotVersion is the current version of the brain snapshot file
+const snapshotVersion = 7
+
+var (
+	// ErrSnapshotMismatch is returned when a snapshot was made with different chain length, end token,
+	// quality filter, half-life, name chain, punctuation, split characters or normalization settings than
+	// the current ones, meaning the brain has to be rebuilt
+	ErrSnapshotMismatch = errors.New("Snapshot does not match the brain settings")
+	// ErrBadSnapshot is returned when the snapshot file is not a brain snapshot
+	ErrBadSnapshot = errors.New("Not a brain snapshot")
+)
+
+// SaveSnapshot writes the brain to the given path, along with the ID of the last message
+// that was fed into it. The file is written to a temporary path first, so that an
+// interrupted save never leaves a broken snapshot behind.
+func (b Brain) SaveSnapshot(path string, lastMessageID int) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrap(err, "os.Create")
+	}
+	// Write the header
+	header := make([]byte, len(snapshotMagic), len(snapshotMagic)+binary.MaxVarintLen64*7)
+	copy(header, snapshotMagic)
+	header = appendUvarint(header, snapshotVersion)
+	header = appendUvarint(header, uint64(lastMessageID))
+	// Followed by the half-life in seconds and the epoch of the decay, 0 for both without decay
+	var halfLife float64
+	var epoch int64
+	if b.decay != nil {
+		halfLife, epoch = b.decay.halfLife, b.decay.epoch
+	}
+	header = appendUvarint(header, math.Float64bits(halfLife))
+	header = appendUvarint(header, uint64(epoch))
+	// And whether the name chain follows the chain
+	var hasNames uint64
+	if b.names != nil {
+		hasNames = 1
+	}
+	header = appendUvarint(header, hasNames)
+	// And whether punctuation was kept when splitting the fed messages
+	var punctuation uint64
+	if b.config.KeepPunctuation {
+		punctuation = 1
+	}
+	header = appendUvarint(header, punctuation)
+	// And how the fed messages were normalized and split
+	header = appendUvarint(header, normalizeHash(b.config.Normalize))
+	header = appendUvarint(header, uint64(len(b.config.SplitChars)))
+	header = append(header, b.config.SplitChars...)
+	if _, err := file.Write(header); err != nil {
+		file.Close()
+		return errors.Wrap(err, "header")
+	}
+	// Then what the quality filters remember of the fed messages
+	if err := b.sources.save(bufio.NewWriter(file)); err != nil {
+		file.Close()
+		return errors.Wrap(err, "sources")
+	}
+	// And the chain itself, followed by the name chain if there is one
+	if err := b.chain.Save(file); err != nil {
+		file.Close()
+		return errors.Wrap(err, "chain.Save")
+	}
+	if b.names != nil {
+		if err := b.names.Save(file); err != nil {
+			file.Close()
+			return errors.Wrap(err, "names.Save")
+		}
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "file.Close")
+	}
+	return errors.Wrap(os.Rename(tmpPath, path), "os.Rename")
+}
+
+// LoadSnapshot loads a brain snapshot from the given path. Returns the brain and the ID of the
+// last message that was fed into it, so that only newer messages have to be fed.
+// Only the Markov chain generator has snapshots, ErrSnapshotMismatch is returned for any other.
+func LoadSnapshot(path string, brainSettings settings.Brain) (Brain, int, error) {
+	if brainSettings.Generator != "" && brainSettings.Generator != GeneratorMarkov {
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "os.Open")
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	// Read the header
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return Brain{}, 0, errors.Wrap(err, "header")
+	}
+	if string(magic) != snapshotMagic {
+		return Brain{}, 0, ErrBadSnapshot
+	}
+	version, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "version")
+	}
+	if version != snapshotVersion {
+		return Brain{}, 0, ErrBadSnapshot
+	}
+	lastMessageID, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "lastMessageID")
+	}
+	halfLife, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "halfLife")
+	}
+	epoch, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "epoch")
+	}
+	hasNames, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "names")
+	}
+	punctuation, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "punctuation")
+	}
+	if (punctuation != 0) != brainSettings.KeepPunctuation {
+		// The fed messages were split into different words
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	normalized, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "normalize")
+	}
+	if normalized != normalizeHash(brainSettings.Normalize) {
+		// The fed messages were normalized differently
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	splitChars, err := readString(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "splitChars")
+	}
+	if !brainSettings.KeepPunctuation && splitChars != brainSettings.SplitChars {
+		// The fed messages were split into different words
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	dec := newDecay(brainSettings, int64(epoch))
+	if (dec == nil && halfLife != 0) || (dec != nil && math.Float64bits(dec.halfLife) != halfLife) {
+		// Snapshots with a different half-life have every message weighed differently
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+
+	// Then the sources and the chain
+	src, err := loadSources(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "sources")
+	}
+	chain, err := gomarkov.Load(reader)
+	if err != nil {
+		return Brain{}, 0, errors.Wrap(err, "gomarkov.Load")
+	}
+	// Snapshots from before end tokens were enabled have to be rebuilt, as do ones with a different length,
+	// and ones that remember different things about the fed messages
+	if chain.LinksLength() != brainSettings.ChainLength || chain.EndTokens() != brainSettings.EndTokens ||
+		!src.matches(brainSettings) {
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	var names *gomarkov.Chain
+	if hasNames != 0 {
+		if names, err = gomarkov.Load(reader); err != nil {
+			return Brain{}, 0, errors.Wrap(err, "gomarkov.Load names")
+		}
+	}
+	if expected := newNames(brainSettings); (names == nil) != (expected == nil) ||
+		(names != nil && names.LinksLength() != expected.LinksLength()) {
+		return Brain{}, 0, ErrSnapshotMismatch
+	}
+	// The backoff tables are not stored, they're built from the chain
+	chain.SetBackoff(brainSettings.Backoff)
+	chain.SetSampling(sampling(brainSettings))
+	return Brain{
+		lock:    &sync.RWMutex{},
+		chain:   chain,
+		config:  &brainSettings,
+		sources: src,
+		decay:   dec,
+		names:   names,
+	}, int(lastMessageID), nil
+}
+
+// readString reads a string written as its length followed by its bytes, of at most maxSnapshotString bytes
+func readString(reader *bufio.Reader) (string, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return "", err
+	}
+	if length > maxSnapshotString {
+		return "", ErrBadSnapshot
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, v)
+	return append(b, buf[:n]...)
+}
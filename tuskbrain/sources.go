@@ -0,0 +1,282 @@
+package tuskbrain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"math"
+
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// maxSourcesPerNgram is the most messages remembered for a single n-gram. An n-gram that appears
+// in more messages than this is a common phrase, so using it doesn't mean copying any one message.
+const maxSourcesPerNgram = 64
+
+// sources remembers the messages fed to the brain, so that generated messages which copy one can be told apart.
+// Only hashes of the messages are kept, and only for the checks enabled in the settings.
+type sources struct {
+	// messages counts how many times every message was fed, by the hash of its words
+	messages map[uint64]int
+	// ngrams contains the hashes of the messages every n-gram appeared in, by the hash of the n-gram
+	ngrams map[uint64][]uint64
+	// ngramLength is the length of the n-grams, 0 if n-grams are not kept
+	ngramLength int
+}
+
+// newSources creates the sources for the checks enabled in the given settings, nil if there are none
+func newSources(brainSettings settings.Brain) *sources {
+	src := &sources{}
+	if brainSettings.RejectCopies {
+		src.messages = make(map[uint64]int)
+	}
+	if brainSettings.MaxOverlap > 0 && brainSettings.OverlapLength > 0 {
+		src.ngrams = make(map[uint64][]uint64)
+		src.ngramLength = brainSettings.OverlapLength
+	}
+	if src.messages == nil && src.ngrams == nil {
+		return nil
+	}
+	return src
+}
+
+// matches reports whether the sources keep what the given settings check for
+func (s *sources) matches(brainSettings settings.Brain) bool {
+	return s.equal(newSources(brainSettings))
+}
+
+func (s *sources) equal(other *sources) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return (s.messages == nil) == (other.messages == nil) &&
+		(s.ngrams == nil) == (other.ngrams == nil) &&
+		s.ngramLength == other.ngramLength
+}
+
+// clone returns a copy of the sources
+func (s *sources) clone() *sources {
+	if s == nil {
+		return nil
+	}
+	clone := &sources{ngramLength: s.ngramLength}
+	if s.messages != nil {
+		clone.messages = make(map[uint64]int, len(s.messages))
+		for message, count := range s.messages {
+			clone.messages[message] = count
+		}
+	}
+	if s.ngrams != nil {
+		clone.ngrams = make(map[uint64][]uint64, len(s.ngrams))
+		for ngram, list := range s.ngrams {
+			clone.ngrams[ngram] = append([]uint64(nil), list...)
+		}
+	}
+	return clone
+}
+
+// reset forgets every fed message
+func (s *sources) reset() {
+	if s == nil {
+		return
+	}
+	if s.messages != nil {
+		s.messages = make(map[uint64]int)
+	}
+	if s.ngrams != nil {
+		s.ngrams = make(map[uint64][]uint64)
+	}
+}
+
+// hashWords returns the hash of the given words
+func hashWords(words []string) uint64 {
+	h := fnv.New64a()
+	for _, word := range words {
+		h.Write([]byte(word))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// add remembers a fed message, or forgets one when count is negative
+func (s *sources) add(words []string, count int) {
+	if s == nil || len(words) == 0 {
+		return
+	}
+	message := hashWords(words)
+	if s.messages != nil {
+		s.messages[message] += count
+		if s.messages[message] <= 0 {
+			delete(s.messages, message)
+		}
+	}
+	if s.ngrams == nil {
+		return
+	}
+	for _, ngram := range s.ngramHashes(words) {
+		if count > 0 {
+			if len(s.ngrams[ngram]) < maxSourcesPerNgram {
+				s.ngrams[ngram] = append(s.ngrams[ngram], message)
+			}
+			continue
+		}
+		// Forget one appearance of the message
+		list := s.ngrams[ngram]
+		for i, source := range list {
+			if source == message {
+				list[i] = list[len(list)-1]
+				list = list[:len(list)-1]
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(s.ngrams, ngram)
+		} else {
+			s.ngrams[ngram] = list
+		}
+	}
+}
+
+// ngramHashes returns the hashes of every distinct n-gram in the given words
+func (s *sources) ngramHashes(words []string) []uint64 {
+	var hashes []uint64
+	seen := make(map[uint64]bool)
+	for i := 0; i+s.ngramLength <= len(words); i++ {
+		hash := hashWords(words[i : i+s.ngramLength])
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
+
+// isCopy reports whether the given words are exactly a fed message
+func (s *sources) isCopy(words []string) bool {
+	if s == nil || s.messages == nil {
+		return false
+	}
+	return s.messages[hashWords(words)] > 0
+}
+
+// overlap returns the largest share of the n-grams of the given words that appeared in a single fed message.
+// Messages shorter than an n-gram have no overlap.
+func (s *sources) overlap(words []string) float64 {
+	if s == nil || s.ngrams == nil {
+		return 0
+	}
+	ngrams := s.ngramHashes(words)
+	if len(ngrams) == 0 {
+		return 0
+	}
+	shared := make(map[uint64]int)
+	most := 0
+	for _, ngram := range ngrams {
+		// A message can contain the same n-gram more than once, only count it once
+		counted := make(map[uint64]bool)
+		for _, message := range s.ngrams[ngram] {
+			if counted[message] {
+				continue
+			}
+			counted[message] = true
+			shared[message]++
+			if shared[message] > most {
+				most = shared[message]
+			}
+		}
+	}
+	return float64(most) / float64(len(ngrams))
+}
+
+// save writes the sources to the given Writer
+func (s *sources) save(w *bufio.Writer) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	uvarint := func(v uint64) {
+		n := binary.PutUvarint(buf, v)
+		w.Write(buf[:n])
+	}
+	if s == nil {
+		uvarint(0)
+		return w.Flush()
+	}
+	var flags uint64
+	if s.messages != nil {
+		flags |= 1
+	}
+	if s.ngrams != nil {
+		flags |= 2
+	}
+	uvarint(flags)
+	uvarint(uint64(s.ngramLength))
+	uvarint(uint64(len(s.messages)))
+	for message, count := range s.messages {
+		uvarint(message)
+		uvarint(uint64(count))
+	}
+	uvarint(uint64(len(s.ngrams)))
+	for ngram, list := range s.ngrams {
+		uvarint(ngram)
+		uvarint(uint64(len(list)))
+		for _, message := range list {
+			uvarint(message)
+		}
+	}
+	return w.Flush()
+}
+
+// loadSources reads sources written by save from the given Reader
+func loadSources(r io.ByteReader) (*sources, error) {
+	var err error
+	uvarint := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(r)
+		return v
+	}
+	flags := uvarint()
+	if flags == 0 {
+		return nil, err
+	}
+	s := &sources{
+		ngramLength: int(uvarint()),
+	}
+	if flags&1 != 0 {
+		s.messages = make(map[uint64]int)
+	}
+	if flags&2 != 0 {
+		s.ngrams = make(map[uint64][]uint64)
+	}
+	// Corrupt lengths end the loops at the end of the snapshot, never more than a list is allocated up front
+	messages := uvarint()
+	if err == nil && messages != 0 && s.messages == nil {
+		return nil, ErrBadSnapshot
+	}
+	for i := uint64(0); i < messages && err == nil; i++ {
+		message := uvarint()
+		count := uvarint()
+		if err == nil && (count == 0 || count > math.MaxInt32) {
+			return nil, ErrBadSnapshot
+		}
+		s.messages[message] = int(count)
+	}
+	ngrams := uvarint()
+	if err == nil && ngrams != 0 && s.ngrams == nil {
+		return nil, ErrBadSnapshot
+	}
+	for i := uint64(0); i < ngrams && err == nil; i++ {
+		ngram := uvarint()
+		length := uvarint()
+		if err == nil && (length == 0 || length > maxSourcesPerNgram) {
+			return nil, ErrBadSnapshot
+		}
+		list := make([]uint64, length)
+		for j := range list {
+			list[j] = uvarint()
+		}
+		s.ngrams[ngram] = list
+	}
+	return s, err
+}
@@ -0,0 +1,316 @@
+package tuskbrain
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wallnutkraken/gotuskgo/gomarkov"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// commonWordShare is how many times a word has to be fed, for every fed message, to be common.
+// Common words are kept by the Template generator, the rest are replaced.
+const commonWordShare = 0.05
+
+// templateSeparator separates the words of a fed message, as words can contain spaces when
+// the SplitChars setting doesn't have one
+const templateSeparator = "\x00"
+
+// Template is the template Generator, which makes up messages like a game of Mad Libs: it takes a fed
+// message and replaces every word in it that isn't common with another fed word. A word is common when
+// it's fed at least once every 20 messages, keeping those makes the made up messages sound like the
+// fed ones. It needs to be fed a few dozen messages before it makes up anything that wasn't fed.
+type Template struct {
+	// lock guards everything below, copies of the generator share it
+	lock   *sync.RWMutex
+	config *settings.Brain
+	// templates are the fed messages, with their words separated by templateSeparator
+	templates *bag
+	words     *bag
+	// rng picks the seeds, it has its own lock as generating only holds the read lock
+	rngLock *sync.Mutex
+	rng     *rand.Rand
+}
+
+// NewTemplate creates a new, empty, template generator
+func NewTemplate(brainSettings settings.Brain) Template {
+	return NewTemplateWithSource(brainSettings, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewTemplateWithSource creates a new, empty, template generator which uses the given Source
+// for all random choices, making its generation reproducible
+func NewTemplateWithSource(brainSettings settings.Brain, src rand.Source) Template {
+	return Template{
+		lock:      &sync.RWMutex{},
+		config:    &brainSettings,
+		templates: newBag(),
+		words:     newBag(),
+		rngLock:   &sync.Mutex{},
+		rng:       rand.New(src),
+	}
+}
+
+// Feed feeds the given messages, received just now, to the generator
+func (t Template) Feed(messages ...string) {
+	t.FeedAt(time.Now().Unix(), messages...)
+}
+
+// FeedAt feeds the given messages to the generator. Every message weighs the same,
+// no matter when it was received. Returns the amount of templates that weren't fed before.
+func (t Template) FeedAt(unix int64, messages ...string) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	templates := len(t.templates.items)
+	for _, msg := range messages {
+		words := tokenize(t.config, msg)
+		if len(words) == 0 {
+			continue
+		}
+		t.templates.add(strings.Join(words, templateSeparator), 1)
+		for _, word := range words {
+			t.words.add(word, 1)
+		}
+	}
+	return len(t.templates.items) - templates
+}
+
+// UnfeedAt removes the given messages from the generator, as if they were never fed
+func (t Template) UnfeedAt(unix int64, messages ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, msg := range messages {
+		words := tokenize(t.config, msg)
+		template := strings.Join(words, templateSeparator)
+		if len(words) == 0 || t.templates.count(template) == 0 {
+			continue
+		}
+		t.templates.add(template, -1)
+		for _, word := range words {
+			t.words.add(word, -1)
+		}
+	}
+}
+
+// Reset forgets every fed message, keeping the settings
+func (t Template) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	*t.templates = *newBag()
+	*t.words = *newBag()
+}
+
+// UpdateSettings replaces the brain settings, the ones that change how messages are split only apply
+// to what's fed afterwards, see NeedsRebuild
+func (t Template) UpdateSettings(brainSettings settings.Brain) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	*t.config = brainSettings
+}
+
+// Generate makes up a new message out of a fed one
+func (t Template) Generate() string {
+	generated, _ := t.GenerateSeeded()
+	return generated
+}
+
+// GenerateSeeded makes up a new message out of a fed one, along with the seed it was generated with
+func (t Template) GenerateSeeded() (string, int64) {
+	seed := t.seed()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return detokenize(t.config, t.generate(rand.New(rand.NewSource(seed)))), seed
+}
+
+// Regenerate makes up the message GenerateSeeded made up with the given seed. As long as nothing was
+// fed or removed since, it's the same message.
+func (t Template) Regenerate(seed int64) string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return detokenize(t.config, t.generate(rand.New(rand.NewSource(seed))))
+}
+
+// GenerateFrom makes up a new message out of a fed one, replacing its first words with the words of the
+// given topic. Falls back to Generate if the topic is empty, or if none of its words were fed.
+func (t Template) GenerateFrom(topic string) string {
+	rng := rand.New(rand.NewSource(t.seed()))
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	seed := tokenize(t.config, topic)
+	known := false
+	for _, word := range seed {
+		known = known || t.words.count(word) != 0
+	}
+	words := t.generate(rng)
+	if known && len(words) > len(seed) {
+		words = append(seed, words[len(seed):]...)
+	} else if known {
+		words = seed
+		if len(words) > t.config.MaxGeneratedLength {
+			words = words[:t.config.MaxGeneratedLength]
+		}
+	}
+	return detokenize(t.config, words)
+}
+
+// GenerateAround makes up a new message out of a fed one, putting the given word in place of one of the
+// words that would have been replaced. The word is normalized the way fed messages are, and Generate is
+// fallen back to if it was never fed.
+func (t Template) GenerateAround(word string) string {
+	rng := rand.New(rand.NewSource(t.seed()))
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	word = tokenizeWord(t.config, word)
+	words := t.generate(rng)
+	if t.words.count(word) == 0 || len(words) == 0 {
+		return detokenize(t.config, words)
+	}
+	// Any word will do if there's nothing to replace
+	var slots []int
+	for i, w := range words {
+		if !t.common(w) {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) == 0 {
+		words[rng.Intn(len(words))] = word
+	} else {
+		words[slots[rng.Intn(len(slots))]] = word
+	}
+	return detokenize(t.config, words)
+}
+
+// GenerateName always returns an empty string, as the template generator doesn't make up words
+func (t Template) GenerateName(prefix string) string {
+	return ""
+}
+
+// Stats returns the statistics of the generator, with the top most frequent words. Links are the
+// distinct fed messages and Transitions the fed words, as there's no chain to describe.
+func (t Template) Stats(top int) gomarkov.Stats {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	words := make([]gomarkov.WordCount, len(t.words.items))
+	for i, word := range t.words.items {
+		words[i] = gomarkov.WordCount{Word: word, Count: t.words.counts[i]}
+	}
+	// Sort the words the same way the chain does
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+	if top < 0 {
+		top = 0
+	}
+	if top < len(words) {
+		words = words[:top]
+	}
+	return gomarkov.Stats{
+		Links:       len(t.templates.items),
+		Transitions: t.words.total,
+		Vocabulary:  len(t.words.items),
+		TopWords:    words,
+	}
+}
+
+// seed returns a new random seed for generating a message
+func (t Template) seed() int64 {
+	t.rngLock.Lock()
+	defer t.rngLock.Unlock()
+	return t.rng.Int63()
+}
+
+// generate picks a fed message and replaces every word in it that isn't common with a random fed word,
+// returning at most MaxGeneratedLength words. The caller should hold the read lock.
+func (t Template) generate(rng *rand.Rand) []string {
+	template := t.templates.pick(rng)
+	if template == "" {
+		return nil
+	}
+	words := strings.Split(template, templateSeparator)
+	if len(words) > t.config.MaxGeneratedLength {
+		words = words[:t.config.MaxGeneratedLength]
+	}
+	for i, word := range words {
+		if !t.common(word) {
+			words[i] = t.words.pick(rng)
+		}
+	}
+	return words
+}
+
+// common reports whether the given word is fed often enough to be kept in templates
+func (t Template) common(word string) bool {
+	return float64(t.words.count(word)) >= commonWordShare*float64(t.templates.total)
+}
+
+// bag holds the templates or words fed to the template generator, along with how many times each one
+// was fed, so that the ones fed more often are picked more often. Removing the last copy of a string
+// forgets it, which is how unfed messages leave the generator.
+type bag struct {
+	items  []string
+	counts []int
+	total  int
+	index  map[string]int
+}
+
+func newBag() *bag {
+	return &bag{index: make(map[string]int)}
+}
+
+// count returns how many times the given string is in the bag
+func (b *bag) count(item string) int {
+	if i, ok := b.index[item]; ok {
+		return b.counts[i]
+	}
+	return 0
+}
+
+// add adds count copies of the given string, a negative count removes copies instead.
+// A string with no copies left is removed, moving the last string into its place.
+func (b *bag) add(item string, count int) {
+	i, ok := b.index[item]
+	if !ok {
+		if count <= 0 {
+			return
+		}
+		i = len(b.items)
+		b.index[item] = i
+		b.items = append(b.items, item)
+		b.counts = append(b.counts, 0)
+	}
+	if b.counts[i]+count < 0 {
+		count = -b.counts[i]
+	}
+	b.counts[i] += count
+	b.total += count
+	if b.counts[i] == 0 {
+		last := len(b.items) - 1
+		delete(b.index, item)
+		if i != last {
+			b.index[b.items[last]] = i
+		}
+		b.items[i], b.counts[i] = b.items[last], b.counts[last]
+		b.items, b.counts = b.items[:last], b.counts[:last]
+	}
+}
+
+// pick returns a random string, weighted by how many copies of it there are, or an empty string
+// if the bag is empty. Takes time linear in the amount of distinct strings.
+func (b *bag) pick(rng *rand.Rand) string {
+	if b.total == 0 {
+		return ""
+	}
+	target := rng.Intn(b.total)
+	for i, count := range b.counts {
+		target -= count
+		if target < 0 {
+			return b.items[i]
+		}
+	}
+	return b.items[len(b.items)-1]
+}
@@ -0,0 +1,45 @@
+package tuskbrain
+
+import (
+	"strings"
+
+	"github.com/wallnutkraken/gotuskgo/stringer"
+	"github.com/wallnutkraken/gotuskgo/tuskbrain/settings"
+)
+
+// tokenize normalizes the given message and splits it into the words fed to the brain. With the KeepPunctuation
+// setting, punctuation is kept as words of its own, otherwise the message is split on the SplitChars setting.
+func tokenize(brainSettings *settings.Brain, message string) []string {
+	message = Normalize(brainSettings.Normalize, message)
+	if brainSettings.KeepPunctuation {
+		return stringer.Tokenize(message)
+	}
+	return stringer.SplitMultiple(message, brainSettings.SplitChars)
+}
+
+// tokenizeWord normalizes and splits the given word the way fed messages are, returning the first word
+// of it, or an empty string if nothing is left of it
+func tokenizeWord(brainSettings *settings.Brain, word string) string {
+	words := tokenize(brainSettings, word)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
+
+// detokenize joins generated words back into a message, without spaces around punctuation
+// when the KeepPunctuation setting is on
+func detokenize(brainSettings *settings.Brain, words []string) string {
+	if brainSettings.KeepPunctuation {
+		return stringer.Detokenize(words)
+	}
+	return strings.Join(words, " ")
+}
+
+// detokenizeText is detokenize for a message generated by the chain, which has its words separated by spaces
+func detokenizeText(brainSettings *settings.Brain, text string) string {
+	if !brainSettings.KeepPunctuation {
+		return text
+	}
+	return stringer.Detokenize(strings.Fields(text))
+}